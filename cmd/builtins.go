@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rogueserenity/stenciler/config"
+	"github.com/rogueserenity/stenciler/git"
+)
+
+// newBuiltIns creates the built-in values for the template in the current directory.
+func newBuiltIns(template *config.Template, mode string) *config.BuiltIns {
+	cwd, err := os.Getwd()
+	if err != nil {
+		checkErr(err)
+	}
+
+	commit, err := git.HeadCommit(repoDir)
+	if err != nil {
+		slog.Debug("unable to determine template commit", slog.String("repoDir", repoDir), slog.Any("error", err))
+	}
+
+	return &config.BuiltIns{
+		TargetDir:  filepath.Base(cwd),
+		Repository: template.Repository,
+		Directory:  template.Directory,
+		Commit:     commit,
+		Version:    rootCmd.Version,
+		Mode:       mode,
+		Now:        time.Now(),
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rogueserenity/stenciler/config"
+	"github.com/rogueserenity/stenciler/prompt"
+)
+
+var (
+	cleanupLock  sync.Mutex
+	cleanupPaths []string
+)
+
+// removeOnExit registers a temporary path to be removed when stenciler exits, whether it completes, fails or is
+// stopped by a signal.
+func removeOnExit(path string) {
+	cleanupLock.Lock()
+	defer cleanupLock.Unlock()
+	cleanupPaths = append(cleanupPaths, path)
+}
+
+// cleanup removes the temporary paths registered with removeOnExit.
+func cleanup() {
+	cleanupLock.Lock()
+	defer cleanupLock.Unlock()
+	for _, path := range cleanupPaths {
+		if err := os.RemoveAll(path); err != nil {
+			slog.Debug("unable to remove temporary path", slog.String("path", path), slog.Any("error", err))
+		}
+	}
+	cleanupPaths = nil
+}
+
+// checkErr cleans up and exits if msg is not nil. It is used in place of cobra.CheckErr, which exits without running
+// deferred functions. If stenciler was stopped by a signal, the exit code reflects the signal.
+func checkErr(msg any) {
+	if msg == nil {
+		return
+	}
+	cleanup()
+	var interrupted *config.InterruptedError
+	if err, ok := msg.(error); ok && errors.As(err, &interrupted) {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(interrupted.ExitCode())
+	}
+	cobra.CheckErr(msg)
+}
+
+// handleSignals stops stenciler when SIGINT or SIGTERM is received. The terminal is restored in case the signal
+// arrived while a secret was being read, and any running hooks are forwarded the signal. If the template is being
+// written, writing stops before the next step and the on-failure hooks are run. Otherwise stenciler cleans up and exits
+// once the hooks have exited. A second signal kills any running hooks and exits immediately.
+func handleSignals() {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		slog.Debug("received signal", slog.String("signal", sig.String()))
+		prompt.RestoreTerminal()
+		config.Interrupt(sig)
+		if !isWriting() {
+			go func() {
+				config.WaitForHooks()
+				checkErr(config.Interrupted())
+			}()
+		}
+
+		sig = <-signals
+		slog.Debug("received second signal", slog.String("signal", sig.String()))
+		config.KillHooks()
+		checkErr(config.Interrupted())
+	}()
+}
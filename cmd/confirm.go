@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rogueserenity/stenciler/config"
+	"github.com/rogueserenity/stenciler/files"
+	"github.com/rogueserenity/stenciler/prompt"
+)
+
+var (
+	assumeYes bool
+)
+
+// addYesFlag adds the flag that skips the confirmation before writing to the command.
+func addYesFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(
+		&assumeYes,
+		"yes",
+		"y",
+		false,
+		"write without showing a summary and asking for confirmation",
+	)
+}
+
+// confirmWrite shows a summary of what is about to be written and asks the user to confirm it, unless the yes flag
+// was given. The summary is also skipped when standard input is not a terminal so that scripts that pipe in the param
+// values are not asked for a confirmation they cannot give.
+func confirmWrite(template *config.Template) {
+	if assumeYes || !prompt.Interactive() {
+		return
+	}
+
+	fileList, err := files.List(repoDir, template)
+	if err != nil {
+		checkErr(err)
+	}
+
+	err = prompt.ConfirmWrite(template, repoDir, fileList)
+	if errors.Is(err, prompt.ErrAborted) {
+		checkErr("aborted, nothing was written")
+	}
+	if err != nil {
+		checkErr(err)
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/rogueserenity/stenciler/config"
+)
+
+var (
+	writeLock sync.Mutex
+	// writing is the template being written, or nil if writing has not started or has finished.
+	writing *config.Template
+)
+
+// writeTemplate runs the steps that write the template in order. If a step fails, or stenciler is stopped by a signal
+// before a step starts or before the last step completes, the on-failure hooks of the template are run and stenciler
+// exits with the error.
+func writeTemplate(template *config.Template, steps ...func() error) {
+	startWrite(template)
+	for _, step := range steps {
+		if err := step(); err != nil {
+			abortWrite(err)
+		}
+		if err := config.Interrupted(); err != nil {
+			abortWrite(err)
+		}
+	}
+	finishWrite()
+}
+
+// startWrite records that writing the template has started so that a signal stops it before the next step rather
+// than stopping stenciler outright. It exits if stenciler has already been stopped by a signal.
+func startWrite(template *config.Template) {
+	writeLock.Lock()
+	defer writeLock.Unlock()
+	checkErr(config.Interrupted())
+	writing = template
+}
+
+// finishWrite records that writing completed.
+func finishWrite() {
+	writeLock.Lock()
+	defer writeLock.Unlock()
+	writing = nil
+}
+
+// isWriting returns true if the template is being written.
+func isWriting() bool {
+	writeLock.Lock()
+	defer writeLock.Unlock()
+	return writing != nil
+}
+
+// abortWrite runs the on-failure hooks of the template being written and then exits with the error. Any error from the
+// on-failure hooks is reported along with the original error.
+func abortWrite(err error) {
+	writeLock.Lock()
+	template := writing
+	writeLock.Unlock()
+	if template != nil {
+		if hookErr := template.ExecuteHooks(repoDir, config.OnFailureHook); hookErr != nil {
+			err = errors.Join(err, hookErr)
+		}
+	}
+	checkErr(err)
+}
@@ -2,19 +2,19 @@
 
 import (
 	"log/slog"
-	"os"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
 
 	"github.com/rogueserenity/stenciler/config"
 	"github.com/rogueserenity/stenciler/files"
-	"github.com/rogueserenity/stenciler/git"
 	"github.com/rogueserenity/stenciler/prompt"
 )
 
 var (
-	templateDir string
+	templateDir     string
+	answersFile     string
+	saveAnswersFile string
 )
 
 // Command represents the init command.
@@ -22,7 +22,12 @@
 	Args:  cobra.ExactArgs(1),
 	Use:   "init repoURL",
 	Short: "initialize a repository with the specified template",
-	Long:  "Initializes the current directory with the contents of the specified template.",
+	Long: `Initializes the current directory with the contents of the specified template.
+
+Before anything is written, a summary of the parameter values, files and hooks is
+shown for confirmation unless --yes is given or standard input is not a terminal.
+If the template has hooks, you are asked whether to run them. When not running
+interactively, --allow-hooks or --no-hooks must be given for such templates.`,
 
 	Run: func(_ *cobra.Command, args []string) {
 		doInit(args[0])
@@ -37,6 +42,20 @@ func init() {
 		"",
 		"template directory to use from the config file",
 	)
+	initCmd.Flags().StringVar(
+		&answersFile,
+		"answers",
+		"",
+		"answers file to replay the template selection and param values from",
+	)
+	initCmd.Flags().StringVar(
+		&saveAnswersFile,
+		"save-answers",
+		"",
+		"answers file to record the template selection and param values to",
+	)
+	addYesFlag(initCmd)
+	addHookFlags(initCmd)
 	rootCmd.AddCommand(initCmd)
 }
 
@@ -46,19 +65,24 @@ func doInit(repoURL string) {
 		slog.String("repoDir", repoDir),
 		slog.Bool("authTokenProvided", len(authToken) > 0),
 		slog.String("templateDir", templateDir),
+		slog.String("answersFile", answersFile),
+		slog.String("saveAnswersFile", saveAnswersFile),
 	)
 
+	var answers *config.Answers
 	var err error
-	if len(repoDir) == 0 {
-		repoDir, err = git.Clone(repoURL, authToken)
+	if len(answersFile) > 0 {
+		answers, err = config.ReadAnswersFromFile(answersFile)
 		if err != nil {
-			cobra.CheckErr(err)
+			checkErr(err)
+		}
+		if len(templateDir) == 0 {
+			templateDir = answers.Directory
 		}
-		defer os.RemoveAll(repoDir)
-		slog.Debug("cloned repository",
-			slog.String("repoURL", repoURL),
-			slog.String("repoDir", repoDir),
-		)
+	}
+
+	if len(repoDir) == 0 {
+		repoDir = cloneRepo(repoURL)
 	}
 
 	cfgFile := filepath.Join(repoDir, configFileName)
@@ -69,63 +93,81 @@ func doInit(repoURL string) {
 	)
 	cfg, err := config.ReadFromFile(cfgFile)
 	if err != nil {
-		cobra.CheckErr(err)
+		checkErr(err)
 	}
 
 	slog.Debug("config", slog.Any("config", *cfg))
 
 	if len(cfg.Templates) == 0 {
-		cobra.CheckErr("no templates found in config file")
+		checkErr("no templates found in config file")
 	}
 
 	template, err := prompt.SelectTemplate(templateDir, cfg)
 	if err != nil {
-		cobra.CheckErr(err)
+		checkErr(err)
 	}
 	localConfig := &config.Config{
 		Templates: []*config.Template{template},
 	}
 	template.Repository = repoURL
+	template.BuiltIns = newBuiltIns(template, config.InitMode)
+	userConfig := readUserConfig()
+	applyUserConfig(template, userConfig)
 
 	err = template.Validate(repoDir)
 	if err != nil {
-		cobra.CheckErr(err)
+		checkErr(err)
 	}
 
-	err = prompt.ForParamValues(template, repoDir)
-	if err != nil {
-		cobra.CheckErr(err)
-	}
+	trustHooks(template, userConfig)
 
-	initialWrite(localConfig)
-}
-
-func initialWrite(localConfig *config.Config) {
-	slog.Debug("writing config file", slog.Any("localConfig", localConfig))
-	err := localConfig.WriteToFile(configFileName)
-	if err != nil {
-		cobra.CheckErr(err)
+	if answers != nil {
+		err = answers.Apply(template)
+		if err != nil {
+			checkErr(err)
+		}
 	}
 
-	template := localConfig.Templates[0]
-
-	err = template.ExecuteHooks(repoDir, config.PreInitHook)
+	err = prompt.ForParamValues(template, repoDir)
 	if err != nil {
-		cobra.CheckErr(err)
+		checkErr(err)
 	}
 
-	err = files.CopyRaw(repoDir, template)
-	if err != nil {
-		cobra.CheckErr(err)
-	}
+	confirmWrite(template)
 
-	err = files.CopyTemplated(repoDir, template)
-	if err != nil {
-		cobra.CheckErr(err)
+	if len(saveAnswersFile) > 0 {
+		err = config.NewAnswers(template).WriteToFile(saveAnswersFile)
+		if err != nil {
+			checkErr(err)
+		}
 	}
 
-	err = template.ExecuteHooks(repoDir, config.PostInitHook)
-	if err != nil {
-		cobra.CheckErr(err)
-	}
+	initialWrite(localConfig)
+}
+
+func initialWrite(localConfig *config.Config) {
+	template := localConfig.Templates[0]
+	writeTemplate(template,
+		func() error {
+			slog.Debug("writing config file", slog.Any("localConfig", localConfig))
+			return localConfig.WriteToFile(configFileName)
+		},
+		func() error {
+			return template.ExecuteHooks(repoDir, config.PreInitHook)
+		},
+		func() error {
+			// The config file is written again so that the params set by the outputs of the pre-init hooks are
+			// recorded.
+			return localConfig.WriteToFile(configFileName)
+		},
+		func() error {
+			return files.CopyRaw(repoDir, template)
+		},
+		func() error {
+			return files.CopyTemplated(repoDir, template)
+		},
+		func() error {
+			return template.ExecuteHooks(repoDir, config.PostInitHook)
+		},
+	)
 }
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rogueserenity/stenciler/config"
+)
+
+const (
+	tableOutput = "table"
+	jsonOutput  = "json"
+)
+
+var (
+	outputFormat string
+)
+
+// templateInfo is the description of a template written by the params command.
+type templateInfo struct {
+	Directory string       `json:"directory"`
+	Params    []*paramInfo `json:"params"`
+}
+
+// paramInfo is the description of a single param written by the params command.
+type paramInfo struct {
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	Internal       bool     `json:"internal"`
+	Prompt         string   `json:"prompt,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	Help           string   `json:"help,omitempty"`
+	Examples       []string `json:"examples,omitempty"`
+	Default        string   `json:"default,omitempty"`
+	DefaultFrom    []string `json:"default-from,omitempty"`
+	Value          string   `json:"value,omitempty"`
+	ValidationHook string   `json:"validation-hook,omitempty"`
+	Required       bool     `json:"required,omitempty"`
+	Pattern        string   `json:"pattern,omitempty"`
+	MinLength      int      `json:"min-length,omitempty"`
+	MaxLength      int      `json:"max-length,omitempty"`
+	Min            *float64 `json:"min,omitempty"`
+	Max            *float64 `json:"max,omitempty"`
+	AllowedValues  []string `json:"allowed-values,omitempty"`
+	Rules          []string `json:"rules,omitempty"`
+}
+
+// Command represents the params command.
+var paramsCmd = &cobra.Command{
+	Args:  cobra.MaximumNArgs(1),
+	Use:   "params [repoURL]",
+	Short: "list the parameters of a template",
+	Long: `Lists the parameters defined by the templates in a template repository without
+initializing anything. The repository is either cloned from repoURL or read from
+the directory given by --template-repo-dir.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		doParams(cmd.OutOrStdout(), args)
+	},
+}
+
+func init() {
+	paramsCmd.Flags().StringVarP(
+		&templateDir,
+		"template-dir",
+		"d",
+		"",
+		"template directory to list the parameters of, defaults to all templates",
+	)
+	paramsCmd.Flags().StringVarP(
+		&outputFormat,
+		"output",
+		"o",
+		tableOutput,
+		"output format, either table or json",
+	)
+	rootCmd.AddCommand(paramsCmd)
+}
+
+func doParams(out io.Writer, args []string) {
+	slog.Debug("params called",
+		slog.Any("args", args),
+		slog.String("repoDir", repoDir),
+		slog.String("templateDir", templateDir),
+		slog.String("output", outputFormat),
+	)
+
+	if outputFormat != tableOutput && outputFormat != jsonOutput {
+		checkErr(fmt.Errorf("unknown output format %s", outputFormat))
+	}
+
+	if len(repoDir) == 0 {
+		if len(args) == 0 {
+			checkErr("a repository URL or template repository directory is required")
+		}
+		repoDir = cloneRepo(args[0])
+	}
+
+	cfg, err := config.ReadFromFile(filepath.Join(repoDir, configFileName))
+	if err != nil {
+		checkErr(err)
+	}
+
+	var templates []*templateInfo
+	for _, t := range cfg.Templates {
+		if len(templateDir) > 0 && t.Directory != templateDir {
+			continue
+		}
+		templates = append(templates, newTemplateInfo(t))
+	}
+	if len(templates) == 0 {
+		checkErr("no matching templates found in config file")
+	}
+
+	if outputFormat == jsonOutput {
+		err = writeParamsJSON(out, templates)
+	} else {
+		err = writeParamsTable(out, templates)
+	}
+	if err != nil {
+		checkErr(err)
+	}
+}
+
+func newTemplateInfo(t *config.Template) *templateInfo {
+	info := &templateInfo{
+		Directory: t.Directory,
+		Params:    make([]*paramInfo, 0, len(t.Params)),
+	}
+	for _, p := range t.Params {
+		param := &paramInfo{
+			Name:          p.Name,
+			Type:          paramType(p),
+			Internal:      len(p.Prompt) == 0,
+			Prompt:        p.Prompt,
+			Description:   p.Description,
+			Help:          p.Help,
+			Examples:      p.Examples,
+			Default:       p.Default,
+			DefaultFrom:   p.DefaultFrom,
+			Required:      p.Required,
+			Pattern:       p.Pattern,
+			MinLength:     p.MinLength,
+			MaxLength:     p.MaxLength,
+			Min:           p.Min,
+			Max:           p.Max,
+			AllowedValues: p.AllowedValues,
+			Rules:         p.Rules(),
+		}
+		if p.ValidationHook != nil {
+			param.ValidationHook = p.ValidationHook.String()
+		}
+		if param.Internal && !p.Secret {
+			param.Value = p.Value
+		}
+		info.Params = append(info.Params, param)
+	}
+	return info
+}
+
+// paramType describes the kind of value a param accepts.
+func paramType(p *config.Param) string {
+	switch {
+	case p.Secret:
+		return "secret"
+	case len(p.AllowedValues) > 0:
+		return "choice"
+	case p.Min != nil || p.Max != nil:
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+func writeParamsJSON(out io.Writer, templates []*templateInfo) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(templates)
+}
+
+func writeParamsTable(out io.Writer, templates []*templateInfo) error {
+	for i, t := range templates {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "template: %s\n", t.Directory)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tTYPE\tINTERNAL\tDEFAULT\tPROMPT\tRULES")
+		for _, p := range t.Params {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				p.Name,
+				p.Type,
+				strconv.FormatBool(p.Internal),
+				displayDefault(p),
+				p.Prompt,
+				strings.Join(p.Rules, "; "),
+			)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// displayDefault describes where the default of a param comes from. Default sources are listed in the order they are
+// checked, followed by the fallback default. Internal params show their fixed value.
+func displayDefault(p *paramInfo) string {
+	if p.Internal {
+		return p.Value
+	}
+	sources := p.DefaultFrom
+	if len(p.Default) > 0 {
+		sources = append(slices.Clone(sources), p.Default)
+	}
+	return strings.Join(sources, " > ")
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rogueserenity/stenciler/config"
+)
+
+type ParamsTestSuite struct {
+	suite.Suite
+
+	template *config.Template
+}
+
+func TestParamsTestSuite(t *testing.T) {
+	suite.Run(t, new(ParamsTestSuite))
+}
+
+func (s *ParamsTestSuite) SetupTest() {
+	minCrew := 1.0
+	maxCrew := 9.0
+	s.template = &config.Template{
+		Directory: "serenity",
+		Params: []*config.Param{
+			{
+				Name:        "captain",
+				Prompt:      "Who is the captain?",
+				Description: "The captain of the ship",
+				Help:        "The captain gives the orders",
+				Examples:    []string{"Mal", "Zoe"},
+				DefaultFrom: []string{"env:CAPTAIN", "git:user.name"},
+				Default:     "Mal",
+				Required:    true,
+				Pattern:     "[A-Z][a-z]+",
+				ValidationHook: &config.Hook{
+					Name: "check captain",
+					Run:  "./check-captain.sh",
+				},
+			},
+			{
+				Name:          "class",
+				Prompt:        "What class is the ship?",
+				AllowedValues: []string{"firefly", "reaver"},
+			},
+			{
+				Name:   "crew",
+				Prompt: "How many crew?",
+				Min:    &minCrew,
+				Max:    &maxCrew,
+			},
+			{
+				Name:   "password",
+				Prompt: "What is the password?",
+				Secret: true,
+				Value:  "prompted-secret",
+			},
+			{
+				Name:  "port",
+				Value: "8080",
+			},
+			{
+				Name:   "token",
+				Secret: true,
+				Value:  "internal-secret",
+			},
+		},
+	}
+}
+
+func (s *ParamsTestSuite) TestWriteParamsJSON() {
+	out := &strings.Builder{}
+	err := writeParamsJSON(out, []*templateInfo{newTemplateInfo(s.template)})
+	s.Require().NoError(err)
+
+	expected, err := os.ReadFile(filepath.Join("testdata", "params.json"))
+	s.Require().NoError(err)
+	s.Equal(string(expected), out.String())
+}
+
+func (s *ParamsTestSuite) TestWriteParamsTable() {
+	out := &strings.Builder{}
+	err := writeParamsTable(out, []*templateInfo{newTemplateInfo(s.template), {Directory: "reaver"}})
+	s.Require().NoError(err)
+
+	expected, err := os.ReadFile(filepath.Join("testdata", "params.txt"))
+	s.Require().NoError(err)
+	s.Equal(string(expected), out.String())
+}
+
+func (s *ParamsTestSuite) TestSecretValuesAreHidden() {
+	info := newTemplateInfo(s.template)
+	s.Empty(info.Params[3].Value)
+	s.Empty(info.Params[5].Value)
+	s.Equal("8080", info.Params[4].Value)
+
+	out := &strings.Builder{}
+	s.Require().NoError(writeParamsJSON(out, []*templateInfo{info}))
+	s.Require().NoError(writeParamsTable(out, []*templateInfo{info}))
+	s.NotContains(out.String(), "prompted-secret")
+	s.NotContains(out.String(), "internal-secret")
+}
+
+func (s *ParamsTestSuite) TestParamType() {
+	types := make([]string, 0, len(s.template.Params))
+	for _, p := range s.template.Params {
+		types = append(types, paramType(p))
+	}
+	s.Equal([]string{"string", "choice", "number", "secret", "string", "secret"}, types)
+}
+
+func (s *ParamsTestSuite) TestDisplayDefault() {
+	tests := []struct {
+		name     string
+		param    *paramInfo
+		expected string
+	}{
+		{
+			name:     "none",
+			param:    &paramInfo{},
+			expected: "",
+		},
+		{
+			name:     "default only",
+			param:    &paramInfo{Default: "Mal"},
+			expected: "Mal",
+		},
+		{
+			name:     "sources only",
+			param:    &paramInfo{DefaultFrom: []string{"env:CAPTAIN", "git:user.name"}},
+			expected: "env:CAPTAIN > git:user.name",
+		},
+		{
+			name:     "sources and default",
+			param:    &paramInfo{DefaultFrom: []string{"env:CAPTAIN"}, Default: "Mal"},
+			expected: "env:CAPTAIN > Mal",
+		},
+		{
+			name:     "internal",
+			param:    &paramInfo{Internal: true, Default: "Mal", Value: "8080"},
+			expected: "8080",
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			s.Equal(test.expected, displayDefault(test.param))
+		})
+	}
+}
@@ -6,6 +6,8 @@
 
 	"github.com/carlmjohnson/versioninfo"
 	"github.com/spf13/cobra"
+
+	"github.com/rogueserenity/stenciler/config"
 )
 
 const configFileName = ".stenciler.yaml"
@@ -49,7 +51,11 @@ func SetReleaseVersion(version string) {
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	handleSignals()
 	err := rootCmd.Execute()
+	// A signal that arrived after the command finished its work still stops stenciler with the exit code for it.
+	checkErr(config.Interrupted())
+	cleanup()
 	if err != nil {
 		os.Exit(1)
 	}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Command represents the set command.
+var setCmd = &cobra.Command{
+	Args:  cobra.MinimumNArgs(1),
+	Use:   "set name=value...",
+	Short: "changes the recorded value of template parameters",
+	Long: `Changes the recorded value of one or more prompted template parameters, then
+updates the current directory with the contents of the template using the new
+values.`,
+
+	Run: func(_ *cobra.Command, args []string) {
+		values, err := parseParamAssignments(args)
+		if err != nil {
+			checkErr(err)
+		}
+		doUpdate(values, false, nil)
+	},
+}
+
+func init() {
+	addYesFlag(setCmd)
+	addHookFlags(setCmd)
+	rootCmd.AddCommand(setCmd)
+}
+
+// parseParamAssignments parses a list of name=value arguments into a map of values keyed by name.
+func parseParamAssignments(args []string) (map[string]string, error) {
+	values := make(map[string]string, len(args))
+	for _, arg := range args {
+		name, value, found := strings.Cut(arg, "=")
+		if !found || len(name) == 0 {
+			return nil, fmt.Errorf("invalid assignment %s, expected name=value", arg)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SetTestSuite struct {
+	suite.Suite
+}
+
+func TestSetTestSuite(t *testing.T) {
+	suite.Run(t, new(SetTestSuite))
+}
+
+func (s *SetTestSuite) TestParseParamAssignments() {
+	values, err := parseParamAssignments([]string{"captain=Mal", "motto=a=b", "ship="})
+	s.Require().NoError(err)
+	s.Equal(map[string]string{"captain": "Mal", "motto": "a=b", "ship": ""}, values)
+}
+
+func (s *SetTestSuite) TestParseParamAssignmentsLastWins() {
+	values, err := parseParamAssignments([]string{"captain=Mal", "captain=Zoe"})
+	s.Require().NoError(err)
+	s.Equal(map[string]string{"captain": "Zoe"}, values)
+}
+
+func (s *SetTestSuite) TestParseInvalidParamAssignments() {
+	tests := []string{"captain", "=Mal", ""}
+	for _, arg := range tests {
+		s.Run(arg, func() {
+			_, err := parseParamAssignments([]string{"ship=Serenity", arg})
+			s.Require().EqualError(err, "invalid assignment "+arg+", expected name=value")
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/rogueserenity/stenciler/config"
+	"github.com/rogueserenity/stenciler/prompt"
+)
+
+var (
+	allowHooks bool
+	noHooks    bool
+)
+
+// addHookFlags adds the flags that decide whether hooks are run without asking to the command.
+func addHookFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&allowHooks,
+		"allow-hooks",
+		false,
+		"run the hooks of the template without asking",
+	)
+	cmd.Flags().BoolVar(
+		&noHooks,
+		"no-hooks",
+		false,
+		"skip all hooks of the template, including validation hooks",
+	)
+	cmd.MarkFlagsMutuallyExclusive("allow-hooks", "no-hooks")
+}
+
+// trustHooks decides whether the hooks of the template may be run. Hooks are run without asking if the allow hooks
+// flag was given or the repository is trusted in the user config, and skipped if the no hooks flag was given.
+// Otherwise the user is shown the hooks and asked. Choosing to always run them adds the repository to the trusted
+// repositories in the user config.
+func trustHooks(template *config.Template, userConfig *config.UserConfig) {
+	switch {
+	case noHooks:
+		template.SkipHooks = true
+		return
+	case allowHooks, userConfig.Trusts(template.Repository):
+		return
+	}
+
+	trust, err := prompt.ConfirmHooks(template)
+	if errors.Is(err, io.EOF) {
+		checkErr("standard input ended before the hooks of the template were allowed, " +
+			"use --allow-hooks or --no-hooks when not running interactively")
+	}
+	if err != nil {
+		checkErr(fmt.Errorf("%w, use --allow-hooks or --no-hooks when not running interactively", err))
+	}
+
+	switch trust {
+	case prompt.SkipHooks:
+		template.SkipHooks = true
+	case prompt.AlwaysRunHooks:
+		userConfig.Trust(template.Repository)
+		err = config.WriteUserConfig(userConfig)
+		if err != nil {
+			checkErr(err)
+		}
+		fmt.Fprintf(os.Stderr, "added %s to the trusted repositories\n", template.Repository)
+	case prompt.RunHooks:
+	}
+}
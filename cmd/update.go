@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/spf13/cobra"
 
@@ -14,59 +17,199 @@
 	"github.com/rogueserenity/stenciler/prompt"
 )
 
-// Command represents the init command.
+var (
+	reprompt    bool
+	keepOrphans bool
+)
+
+// Command represents the update command.
 var updateCmd = &cobra.Command{
-	Use:   "update",
+	Use:   "update [--reprompt [name...]]",
 	Short: "updates a repository with the specified template",
-	Long:  "Updates the current directory with the contents of the specified template.",
+	Long: `Updates the current directory with the contents of the specified template.
+
+With --reprompt, the named parameters are prompted for again before the update is
+applied. If no names are given, all prompted parameters are prompted for again.
+
+Before anything is written, a summary of the parameter values, files and hooks is
+shown for confirmation unless --yes is given or standard input is not a terminal.
+If the template has hooks, you are asked whether to run them. When not running
+interactively, --allow-hooks or --no-hooks must be given for such templates.`,
 
-	Run: func(_ *cobra.Command, _ []string) {
-		doUpdate()
+	Args: func(_ *cobra.Command, args []string) error {
+		if !reprompt && len(args) > 0 {
+			return errors.New("parameter names are only accepted with --reprompt")
+		}
+		return nil
+	},
+	Run: func(_ *cobra.Command, args []string) {
+		var names []string
+		if reprompt {
+			names = args
+		}
+		doUpdate(nil, reprompt, names)
 	},
 }
 
 func init() {
+	updateCmd.Flags().BoolVar(
+		&reprompt,
+		"reprompt",
+		false,
+		"prompt again for the named parameters, or all prompted parameters if none are named",
+	)
+	updateCmd.Flags().BoolVar(
+		&keepOrphans,
+		"keep-orphans",
+		false,
+		"keep prompted parameters no longer defined by the template as unmanaged data in the config file",
+	)
+	addYesFlag(updateCmd)
+	addHookFlags(updateCmd)
 	rootCmd.AddCommand(updateCmd)
 }
 
-func doUpdate() {
+// doUpdate updates the current directory from its template. Any values in overrides replace the recorded values of
+// the matching params. If reprompt is true, the params listed in repromptNames, or all prompted params if the list is
+// empty, are prompted for again.
+func doUpdate(overrides map[string]string, reprompt bool, repromptNames []string) {
 	slog.Debug("update called",
 		slog.String("repoDir", repoDir),
 		slog.Bool("authTokenProvided", len(authToken) > 0),
+		slog.Any("overrides", slices.Collect(maps.Keys(overrides))),
+		slog.Bool("reprompt", reprompt),
+		slog.Any("repromptNames", repromptNames),
 	)
 
 	localTemplate := getLocalTemplateConfig()
 
 	if len(repoDir) == 0 {
 		repoDir = cloneRepo(localTemplate.Repository)
-		defer os.RemoveAll(repoDir)
 	}
 
 	repoTemplate := getRepoTemplateConfig(localTemplate.Directory)
 
-	mergedTemplate := config.Merge(repoTemplate, localTemplate)
+	mergedTemplate, err := config.Merge(repoTemplate, localTemplate)
+	if err != nil {
+		checkErr(err)
+	}
+	handleOrphans(mergedTemplate, config.Orphans(repoTemplate, localTemplate))
+	mergedTemplate.Update = true
+	mergedTemplate.BuiltIns = newBuiltIns(mergedTemplate, config.UpdateMode)
+	userConfig := readUserConfig()
+	applyUserConfig(mergedTemplate, userConfig)
 
-	err := mergedTemplate.Validate(repoDir)
+	err = mergedTemplate.Validate(repoDir)
 	if err != nil {
-		cobra.CheckErr(err)
+		checkErr(err)
+	}
+
+	trustHooks(mergedTemplate, userConfig)
+
+	err = setParamValues(mergedTemplate, overrides)
+	if err != nil {
+		checkErr(err)
+	}
+
+	if reprompt {
+		err = clearParamValues(mergedTemplate, repromptNames)
+		if err != nil {
+			checkErr(err)
+		}
 	}
 
 	err = prompt.ForParamValues(mergedTemplate, repoDir)
 	if err != nil {
-		cobra.CheckErr(err)
+		checkErr(err)
 	}
 
-	mergedTemplate.Update = true
+	confirmWrite(mergedTemplate)
 
 	updateWrite(mergedTemplate)
 }
 
+// handleOrphans reports each prompted param that is no longer defined by the template. If keepOrphans is set, they are
+// added to the unmanaged params of the template, otherwise they are dropped.
+func handleOrphans(template *config.Template, orphans []*config.Param) {
+	for _, p := range orphans {
+		slog.Debug("orphaned param", slog.Any("param", *p))
+		if keepOrphans {
+			fmt.Fprintf(os.Stderr, "param %s is no longer defined by the template, keeping value %q as unmanaged\n",
+				p.Name, p.DisplayValue())
+			template.Unmanaged = append(template.Unmanaged, p)
+		} else {
+			fmt.Fprintf(os.Stderr, "param %s is no longer defined by the template, dropping value %q\n",
+				p.Name, p.DisplayValue())
+		}
+	}
+}
+
+// setParamValues replaces the values of the named prompted params. The values are validated when the params are
+// processed.
+func setParamValues(template *config.Template, values map[string]string) error {
+	for name, value := range values {
+		param, err := findPromptedParam(template, name)
+		if err != nil {
+			return err
+		}
+		param.Value = value
+		param.Source = config.FlagValue
+	}
+	return nil
+}
+
+// clearParamValues clears the values of the named prompted params so that they are prompted for again. If no names
+// are given, the values of all prompted params are cleared. The cleared values are offered as the defaults at the
+// prompt, except for secret values, which are never displayed.
+func clearParamValues(template *config.Template, names []string) error {
+	if len(names) == 0 {
+		for _, p := range template.Params {
+			if len(p.Prompt) > 0 {
+				clearParamValue(template, p)
+			}
+		}
+		return nil
+	}
+
+	for _, name := range names {
+		param, err := findPromptedParam(template, name)
+		if err != nil {
+			return err
+		}
+		clearParamValue(template, param)
+	}
+	return nil
+}
+
+func clearParamValue(template *config.Template, param *config.Param) {
+	if !param.Secret && len(param.Value) > 0 {
+		if template.PreviousValues == nil {
+			template.PreviousValues = make(map[string]string)
+		}
+		template.PreviousValues[param.Name] = param.Value
+	}
+	param.Value = ""
+}
+
+func findPromptedParam(template *config.Template, name string) (*config.Param, error) {
+	for _, p := range template.Params {
+		if p.Name != name {
+			continue
+		}
+		if len(p.Prompt) == 0 {
+			return nil, fmt.Errorf("param %s is internal and cannot be changed", name)
+		}
+		return p, nil
+	}
+	return nil, fmt.Errorf("param %s is not defined by the template", name)
+}
+
 func getLocalTemplateConfig() *config.Template {
 	cfgFile := configFileName
 	slog.Debug("local config file path", slog.String("path", cfgFile))
 	cfg, err := config.ReadFromFile(cfgFile)
 	if err != nil {
-		cobra.CheckErr(fmt.Errorf("failed to read config file: %w", err))
+		checkErr(fmt.Errorf("failed to read config file: %w", err))
 	}
 	slog.Debug("local config", slog.Any("config", *cfg))
 	return cfg.Templates[0]
@@ -77,13 +220,13 @@ func getRepoTemplateConfig(templateDir string) *config.Template {
 	slog.Debug("repo config file path", slog.String("path", cfgFile))
 	cfg, err := config.ReadFromFile(cfgFile)
 	if err != nil {
-		cobra.CheckErr(err)
+		checkErr(err)
 	}
 	slog.Debug("repo config", slog.Any("config", *cfg))
 
 	template, err := prompt.SelectTemplate(templateDir, cfg)
 	if err != nil {
-		cobra.CheckErr(err)
+		checkErr(err)
 	}
 
 	return template
@@ -92,8 +235,9 @@ func getRepoTemplateConfig(templateDir string) *config.Template {
 func cloneRepo(repoURL string) string {
 	cloneDir, err := git.Clone(repoURL, authToken)
 	if err != nil {
-		cobra.CheckErr(err)
+		checkErr(err)
 	}
+	removeOnExit(cloneDir)
 	slog.Debug("cloned repository", slog.String("directory", cloneDir))
 	return cloneDir
 }
@@ -102,29 +246,27 @@ func updateWrite(template *config.Template) {
 	localConfig := &config.Config{
 		Templates: []*config.Template{template},
 	}
-	slog.Debug("writing merged config file", slog.Any("config", localConfig))
-	err := localConfig.WriteToFile(configFileName)
-	if err != nil {
-		cobra.CheckErr(err)
-	}
-
-	err = template.ExecuteHooks(repoDir, config.PreUpdateHook)
-	if err != nil {
-		cobra.CheckErr(err)
-	}
-
-	err = files.CopyRaw(repoDir, template)
-	if err != nil {
-		cobra.CheckErr(err)
-	}
-
-	err = files.CopyTemplated(repoDir, template)
-	if err != nil {
-		cobra.CheckErr(err)
-	}
-
-	err = template.ExecuteHooks(repoDir, config.PostUpdateHook)
-	if err != nil {
-		cobra.CheckErr(err)
-	}
+	writeTemplate(template,
+		func() error {
+			slog.Debug("writing merged config file", slog.Any("config", localConfig))
+			return localConfig.WriteToFile(configFileName)
+		},
+		func() error {
+			return template.ExecuteHooks(repoDir, config.PreUpdateHook)
+		},
+		func() error {
+			// The config file is written again so that the params set by the outputs of the pre-update hooks are
+			// recorded.
+			return localConfig.WriteToFile(configFileName)
+		},
+		func() error {
+			return files.CopyRaw(repoDir, template)
+		},
+		func() error {
+			return files.CopyTemplated(repoDir, template)
+		},
+		func() error {
+			return template.ExecuteHooks(repoDir, config.PostUpdateHook)
+		},
+	)
 }
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rogueserenity/stenciler/config"
+)
+
+type UpdateTestSuite struct {
+	suite.Suite
+
+	template *config.Template
+}
+
+func TestUpdateTestSuite(t *testing.T) {
+	suite.Run(t, new(UpdateTestSuite))
+}
+
+func (s *UpdateTestSuite) SetupTest() {
+	s.template = &config.Template{
+		Params: []*config.Param{
+			{
+				Name:   "captain",
+				Prompt: "Captain",
+				Value:  "Mal",
+				Source: config.PromptedValue,
+			},
+			{
+				Name:   "ship",
+				Prompt: "Ship",
+				Value:  "Serenity",
+				Source: config.PromptedValue,
+			},
+			{
+				Name:   "token",
+				Prompt: "Token",
+				Value:  "shiny",
+				Secret: true,
+			},
+			{
+				Name:  "port",
+				Value: "8080",
+			},
+		},
+	}
+}
+
+func (s *UpdateTestSuite) TestFindPromptedParam() {
+	param, err := findPromptedParam(s.template, "ship")
+	s.Require().NoError(err)
+	s.Same(s.template.Params[1], param)
+
+	_, err = findPromptedParam(s.template, "port")
+	s.Require().EqualError(err, "param port is internal and cannot be changed")
+
+	_, err = findPromptedParam(s.template, "pilot")
+	s.Require().EqualError(err, "param pilot is not defined by the template")
+}
+
+func (s *UpdateTestSuite) TestSetParamValues() {
+	err := setParamValues(s.template, map[string]string{"captain": "Zoe"})
+	s.Require().NoError(err)
+
+	s.Equal("Zoe", s.template.Params[0].Value)
+	s.Equal(config.FlagValue, s.template.Params[0].Source)
+	s.Equal("Serenity", s.template.Params[1].Value)
+	s.Equal(config.PromptedValue, s.template.Params[1].Source)
+}
+
+func (s *UpdateTestSuite) TestSetParamValuesRejectsInternalParams() {
+	err := setParamValues(s.template, map[string]string{"port": "9090"})
+	s.Require().EqualError(err, "param port is internal and cannot be changed")
+	s.Equal("8080", s.template.Params[3].Value)
+}
+
+func (s *UpdateTestSuite) TestClearParamValues() {
+	err := clearParamValues(s.template, []string{"ship"})
+	s.Require().NoError(err)
+
+	s.Equal("Mal", s.template.Params[0].Value)
+	s.Empty(s.template.Params[1].Value)
+	s.Equal(map[string]string{"ship": "Serenity"}, s.template.PreviousValues)
+}
+
+func (s *UpdateTestSuite) TestClearAllParamValues() {
+	err := clearParamValues(s.template, nil)
+	s.Require().NoError(err)
+
+	for _, p := range s.template.Params[:3] {
+		s.Empty(p.Value, p.Name)
+	}
+	s.Equal("8080", s.template.Params[3].Value)
+	s.Equal(map[string]string{"captain": "Mal", "ship": "Serenity"}, s.template.PreviousValues)
+}
+
+func (s *UpdateTestSuite) TestClearParamValuesRejectsUnknownParams() {
+	err := clearParamValues(s.template, []string{"captain", "pilot"})
+	s.Require().EqualError(err, "param pilot is not defined by the template")
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import "github.com/rogueserenity/stenciler/config"
+
+// readUserConfig reads the per-user config file shared across all templates.
+func readUserConfig() *config.UserConfig {
+	userConfig, err := config.ReadUserConfig()
+	if err != nil {
+		checkErr(err)
+	}
+	return userConfig
+}
+
+// applyUserConfig sets the per-user values used during execution on the template.
+func applyUserConfig(template *config.Template, userConfig *config.UserConfig) {
+	template.UserDefaults = userConfig.Defaults
+	template.RestrictHookEnv = userConfig.RestrictHookEnv
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Answers holds the template selection and parameter values given when initializing a repository so that they can be
+// replayed.
+type Answers struct {
+	// Directory is the directory of the selected template.
+	Directory string `yaml:"directory,omitempty"`
+	// Params holds the value of each prompted parameter keyed by name. Secret values are never recorded.
+	Params map[string]string `yaml:"params,omitempty"`
+}
+
+// NewAnswers records the template selection and the values of the prompted, non-secret parameters of the template.
+func NewAnswers(template *Template) *Answers {
+	answers := &Answers{
+		Directory: template.Directory,
+		Params:    make(map[string]string),
+	}
+	for _, p := range template.Params {
+		if len(p.Prompt) > 0 && !p.Secret {
+			answers.Params[p.Name] = p.Value
+		}
+	}
+	return answers
+}
+
+// ReadAnswersFromFile attempts to read answers from the specified path.
+func ReadAnswersFromFile(answersPath string) (*Answers, error) {
+	file, err := os.Open(answersPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadAnswers(file)
+}
+
+// ReadAnswers attempts to read answers from the specified reader.
+func ReadAnswers(in io.Reader) (*Answers, error) {
+	b, err := io.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+	answers := &Answers{}
+	err = yaml.Unmarshal(b, answers)
+	if err != nil {
+		return nil, err
+	}
+	return answers, nil
+}
+
+// WriteToFile attempts to write the answers out to the specified path.
+func (a *Answers) WriteToFile(answersPath string) error {
+	file, err := os.Create(answersPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return a.Write(file)
+}
+
+// Write attempts to write the answers to the specified writer.
+func (a *Answers) Write(out io.Writer) error {
+	b, err := yaml.Marshal(a)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(b)
+	return err
+}
+
+// Apply sets the value of each prompted parameter of the template that has an answer. It returns an error if an answer
+// is given for a parameter that the template does not define or that is internal.
+func (a *Answers) Apply(template *Template) error {
+	params := make(map[string]*Param, len(template.Params))
+	for _, p := range template.Params {
+		params[p.Name] = p
+	}
+
+	var errs []error
+	for name, value := range a.Params {
+		p, ok := params[name]
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("answer given for param %s which is not defined by the template", name))
+		case len(p.Prompt) == 0:
+			errs = append(errs, fmt.Errorf("answer given for param %s which is internal", name))
+		default:
+			p.Value = value
+			p.Source = ValuesFileValue
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,118 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rogueserenity/stenciler/config"
+)
+
+type AnswersTestSuite struct {
+	suite.Suite
+
+	answersText string
+	answers     *config.Answers
+}
+
+func TestAnswersTestSuite(t *testing.T) {
+	suite.Run(t, new(AnswersTestSuite))
+}
+
+func (s *AnswersTestSuite) SetupTest() {
+	s.answersText = `directory: foo
+params:
+  ship: Serenity
+  captain: Malcolm
+`
+	s.answers = &config.Answers{
+		Directory: "foo",
+		Params: map[string]string{
+			"ship":    "Serenity",
+			"captain": "Malcolm",
+		},
+	}
+}
+
+func (s *AnswersTestSuite) TestRead() {
+	actual, err := config.ReadAnswers(strings.NewReader(s.answersText))
+	s.Require().NoError(err)
+	s.Require().Equal(s.answers, actual)
+}
+
+func (s *AnswersTestSuite) TestWrite() {
+	writer := &strings.Builder{}
+	err := s.answers.Write(writer)
+	s.Require().NoError(err)
+	s.Require().YAMLEq(s.answersText, writer.String())
+}
+
+func (s *AnswersTestSuite) TestNewAnswers() {
+	template := &config.Template{
+		Directory: "foo",
+		Params: []*config.Param{
+			{
+				Name:   "ship",
+				Prompt: "Ship",
+				Value:  "Serenity",
+			},
+			{
+				Name:   "captain",
+				Prompt: "Captain",
+				Value:  "Malcolm",
+			},
+			{
+				Name:   "token",
+				Prompt: "Token",
+				Secret: true,
+				Value:  "hunter2",
+			},
+			{
+				Name:  "internal",
+				Value: "value",
+			},
+		},
+	}
+	s.Require().Equal(s.answers, config.NewAnswers(template))
+}
+
+func (s *AnswersTestSuite) TestApply() {
+	template := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:   "ship",
+				Prompt: "Ship",
+			},
+			{
+				Name:   "captain",
+				Prompt: "Captain",
+			},
+			{
+				Name:   "pilot",
+				Prompt: "Pilot",
+			},
+		},
+	}
+	err := s.answers.Apply(template)
+	s.Require().NoError(err)
+	s.Require().Equal("Serenity", template.Params[0].Value)
+	s.Require().Equal(config.ValuesFileValue, template.Params[0].Source)
+	s.Require().Equal("Malcolm", template.Params[1].Value)
+	s.Require().Empty(template.Params[2].Value)
+}
+
+func (s *AnswersTestSuite) TestApplyUnknownParams() {
+	template := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:  "ship",
+				Value: "Firefly",
+			},
+		},
+	}
+	err := s.answers.Apply(template)
+	s.Require().ErrorContains(err, "answer given for param ship which is internal")
+	s.Require().ErrorContains(err, "answer given for param captain which is not defined by the template")
+	s.Require().Equal("Firefly", template.Params[0].Value)
+}
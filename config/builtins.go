@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iancoleman/strcase"
+)
+
+const (
+	// BuiltInsName is the reserved name under which the built-in values are available to templates.
+	BuiltInsName = "Stenciler"
+	// BuiltInsEnvPrefix is the prefix of the environment variables that hold the built-in values for hooks.
+	BuiltInsEnvPrefix = "STENCILER_BUILTIN_"
+
+	// InitMode is the mode when initializing a repository.
+	InitMode = "init"
+	// UpdateMode is the mode when updating a repository.
+	UpdateMode = "update"
+)
+
+// BuiltIns holds the values stenciler provides to every template and hook in addition to the parameters. Templates
+// access them under the reserved name, e.g. {{ .Stenciler.TargetDir }}, and hooks receive them as environment
+// variables, e.g. STENCILER_BUILTIN_TARGET_DIR.
+type BuiltIns struct {
+	// TargetDir is the base name of the directory being initialized or updated.
+	TargetDir string
+	// Repository is the URL of the template repository.
+	Repository string
+	// Directory is the directory in the template repository that holds the template data.
+	Directory string
+	// Commit is the commit hash of the template repository. It is empty if the commit cannot be determined.
+	Commit string
+	// Version is the version of stenciler.
+	Version string
+	// Mode is either InitMode or UpdateMode.
+	Mode string
+	// Now is the time the command was started.
+	Now time.Time
+}
+
+// Date returns the date the command was started in YYYY-MM-DD form.
+func (b *BuiltIns) Date() string {
+	return b.Now.Format(time.DateOnly)
+}
+
+// Environ returns the built-in values as environment variable assignments.
+func (b *BuiltIns) Environ() []string {
+	values := b.values()
+	env := make([]string, 0, len(values))
+	for _, v := range values {
+		env = append(env, fmt.Sprintf("%s%s=%s", BuiltInsEnvPrefix, strcase.ToScreamingSnake(v.name), v.value))
+	}
+	return env
+}
+
+// Map returns the built-in values keyed by the names templates use for them, e.g. TargetDir.
+func (b *BuiltIns) Map() map[string]string {
+	values := b.values()
+	m := make(map[string]string, len(values))
+	for _, v := range values {
+		m[v.name] = v.value
+	}
+	return m
+}
+
+type builtInValue struct {
+	name  string
+	value string
+}
+
+func (b *BuiltIns) values() []builtInValue {
+	return []builtInValue{
+		{"TargetDir", b.TargetDir},
+		{"Repository", b.Repository},
+		{"Directory", b.Directory},
+		{"Commit", b.Commit},
+		{"Version", b.Version},
+		{"Mode", b.Mode},
+		{"Date", b.Date()},
+		{"Time", b.Now.Format(time.RFC3339)},
+	}
+}
+
+// validateName ensures that the parameter name does not collide with the built-in values.
+func (p *Param) validateName() error {
+	if p.Name == BuiltInsName {
+		return fmt.Errorf("param name %s is reserved", p.Name)
+	}
+	if strings.HasPrefix(p.envName(), BuiltInsEnvPrefix) {
+		return fmt.Errorf("param %s collides with the reserved environment variable prefix %s",
+			p.Name, BuiltInsEnvPrefix)
+	}
+	return nil
+}
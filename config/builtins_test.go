@@ -0,0 +1,55 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rogueserenity/stenciler/config"
+)
+
+type BuiltInsTestSuite struct {
+	suite.Suite
+}
+
+func TestBuiltInsTestSuite(t *testing.T) {
+	suite.Run(t, new(BuiltInsTestSuite))
+}
+
+func (s *BuiltInsTestSuite) TestEnviron() {
+	builtIns := &config.BuiltIns{
+		TargetDir:  "serenity",
+		Repository: "https://github.com/owner/repo.git",
+		Directory:  "foo",
+		Commit:     "abc123",
+		Version:    "v1.2.3",
+		Mode:       config.InitMode,
+		Now:        time.Date(2024, time.May, 4, 12, 30, 0, 0, time.UTC),
+	}
+	expected := []string{
+		"STENCILER_BUILTIN_TARGET_DIR=serenity",
+		"STENCILER_BUILTIN_REPOSITORY=https://github.com/owner/repo.git",
+		"STENCILER_BUILTIN_DIRECTORY=foo",
+		"STENCILER_BUILTIN_COMMIT=abc123",
+		"STENCILER_BUILTIN_VERSION=v1.2.3",
+		"STENCILER_BUILTIN_MODE=init",
+		"STENCILER_BUILTIN_DATE=2024-05-04",
+		"STENCILER_BUILTIN_TIME=2024-05-04T12:30:00Z",
+	}
+	s.Require().Equal(expected, builtIns.Environ())
+}
+
+func (s *BuiltInsTestSuite) TestValidateReservedNames() {
+	template := &config.Template{
+		Params: []*config.Param{
+			{Name: "Stenciler"},
+			{Name: "builtin_dir"},
+			{Name: "target_dir"},
+		},
+	}
+	err := template.Validate("test-repo")
+	s.Require().ErrorContains(err, "param name Stenciler is reserved")
+	s.Require().ErrorContains(err, "param builtin_dir collides with the reserved environment variable prefix")
+	s.Require().NotContains(err.Error(), "target_dir")
+}
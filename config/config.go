@@ -1,16 +1,16 @@
 package config
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"strings"
 
-	"github.com/iancoleman/strcase"
 	"gopkg.in/yaml.v3"
 )
 
@@ -26,6 +26,69 @@
 	PreUpdateHook
 	// PostUpdateHook is a hook that runs after updating the repository.
 	PostUpdateHook
+	// OnFailureHook is a hook that runs when initializing or updating the repository fails.
+	OnFailureHook
+)
+
+// String returns the name of the hook class as used in the config file.
+func (c HookClass) String() string {
+	switch c {
+	case PreInitHook:
+		return "pre-init"
+	case PostInitHook:
+		return "post-init"
+	case PreUpdateHook:
+		return "pre-update"
+	case PostUpdateHook:
+		return "post-update"
+	case OnFailureHook:
+		return "on-failure"
+	default:
+		return fmt.Sprintf("HookClass(%d)", int(c))
+	}
+}
+
+const redacted = "[REDACTED]"
+
+// InvalidValueExitCode is the exit code a validation hook uses to reject a value. The hook should write the reason
+// the value was rejected to standard error. Any other non-zero exit code is treated as a failure of the hook itself.
+const InvalidValueExitCode = 65
+
+// ValidationError is returned when a parameter value is rejected, either by its declarative rules or by its
+// validation hook. Any other error returned while validating indicates that validation itself could not be completed.
+type ValidationError struct {
+	// Param is the name of the parameter whose value was rejected.
+	Param string
+	// Err describes why the value was rejected.
+	Err error
+}
+
+// Error returns the reason the value was rejected.
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying reason the value was rejected.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ValueSource is an enumeration type for where the value of a parameter came from.
+type ValueSource string
+
+const (
+	// PromptedValue is a value the user entered at the prompt.
+	PromptedValue ValueSource = "prompted"
+	// DefaultValue is a default value the user accepted at the prompt.
+	DefaultValue ValueSource = "default"
+	// ValuesFileValue is a value read from a file of values.
+	ValuesFileValue ValueSource = "values-file"
+	// FlagValue is a value given on the command line.
+	FlagValue ValueSource = "flag"
+	// HookValue is a value rewritten or produced by a hook.
+	HookValue ValueSource = "hook"
+	// InternalValue is a value defined by the template for an internal parameter.
+	InternalValue ValueSource = "internal"
 )
 
 // Param holds all of the values for a parameter.
@@ -36,12 +99,53 @@ type Param struct {
 	// Prompt is the prompt to display to the user when initializing a new repository. Optional. If not provided, the
 	// parameter is considered internal only.
 	Prompt string `yaml:"prompt,omitempty"`
+	// Description is a short summary of the parameter. Optional. It is shown when the user asks for help at the prompt.
+	Description string `yaml:"description,omitempty"`
+	// Help is a longer explanation of the parameter. Optional. It is shown when the user asks for help at the prompt.
+	Help string `yaml:"help,omitempty"`
+	// Examples is a list of example values. Optional. They are shown when the user asks for help at the prompt.
+	Examples []string `yaml:"examples,omitempty"`
 	// Default is the default value to use if the user does not provide one. Optional. An empty string is used as the
 	// default if no default is provided and the user does not set a value.
 	Default string `yaml:"default,omitempty"`
-	// ValidationHook is the path to a script to run to validate the value. Optional. The path is relative to the
-	// repository root.
-	ValidationHook string `yaml:"validation-hook,omitempty"`
+	// DefaultFrom is a list of sources to take the default from. Optional. The sources are checked in order and the first
+	// one with a value is used, falling back to Default if none have a value. Each source is one of git:<key> for a git
+	// config key such as git:user.email, env:<name> for an environment variable or target-dir for the base name of the
+	// directory being initialized.
+	DefaultFrom []string `yaml:"default-from,omitempty"`
+	// ValidationHook is the hook to run to validate the value. Optional. The hook is passed the name of the parameter
+	// and its value as arguments. A secret value is left out of the arguments so that it is not visible in the process
+	// list, and the hook reads it from the STENCILER_<NAME> environment variable instead. The hook rejects a value by
+	// exiting with InvalidValueExitCode and writing the reason to standard error.
+	ValidationHook *Hook `yaml:"validation-hook,omitempty"`
+
+	// Required indicates that an empty value is not accepted. Optional.
+	Required bool `yaml:"required,omitempty"`
+	// Pattern is a regular expression the value must match. Optional. The expression must match the entire value.
+	Pattern string `yaml:"pattern,omitempty"`
+	// MinLength is the minimum number of characters in the value. Optional.
+	MinLength int `yaml:"min-length,omitempty"`
+	// MaxLength is the maximum number of characters in the value. Optional.
+	MaxLength int `yaml:"max-length,omitempty"`
+	// Min is the smallest numeric value allowed. Optional. If set, the value must be a number.
+	Min *float64 `yaml:"min,omitempty"`
+	// Max is the largest numeric value allowed. Optional. If set, the value must be a number.
+	Max *float64 `yaml:"max,omitempty"`
+	// AllowedValues is the list of values the parameter may take. Optional.
+	AllowedValues []string `yaml:"allowed-values,omitempty"`
+	// Secret indicates that the value is sensitive. Optional. Secret values are read without echo, are never written to
+	// the config file and are redacted from log output. They are still passed to hooks and templates.
+	Secret bool `yaml:"secret,omitempty"`
+
+	// RenamedFrom is a list of previous names of the parameter. Optional. When updating, a value recorded under one of
+	// these names is carried forward if no value is recorded under Name. The names are checked in order.
+	RenamedFrom []string `yaml:"renamed-from,omitempty"`
+	// Revision is the revision of the format of the value. Optional. It is recorded along with the value so that
+	// Migrations are only applied to values recorded at an older revision.
+	Revision int `yaml:"revision,omitempty"`
+	// Migrations is a list of transforms applied in revision order to a value recorded at an older revision when
+	// updating. Optional.
+	Migrations []*Migration `yaml:"migrations,omitempty"`
 
 	// Value is the value of the parameter. For a template, this is ignored if Prompt is set.
 	// For a repository, the value is determined by the following rules:
@@ -50,6 +154,19 @@ type Param struct {
 	//    provide a value.
 	// 3. If the parameter has a ValidationHook, then that is executed and the output is the value.
 	Value string `yaml:"value,omitempty"`
+	// Source records where the value came from. It is set when the value is determined and is used when updating to
+	// decide whether a recorded value should be carried forward.
+	Source ValueSource `yaml:"source,omitempty"`
+}
+
+// Migration transforms a parameter value recorded at an older revision.
+type Migration struct {
+	// Revision is the revision of the parameter that this migration upgrades a value to. Required. It must be greater
+	// than zero and no greater than the Revision of the parameter.
+	Revision int `yaml:"revision"`
+	// Transform is a text/template that produces the new value. Required. The recorded value is available as .Value and
+	// all other recorded values are available by name in .Params.
+	Transform string `yaml:"transform"`
 }
 
 // Template holds all of the values for a template configuration. The paths defined by init-only and raw-copy are
@@ -58,6 +175,25 @@ type Template struct {
 	// Update is true if the current command is update. This is not saved in the config file and is only used during
 	// execution.
 	Update bool `yaml:"-"`
+	// BuiltIns holds the built-in values for the current command. This is not saved in the config file and is only
+	// used during execution.
+	BuiltIns *BuiltIns `yaml:"-"`
+	// UserDefaults holds the per-user default values keyed by param name. This is not saved in the config file and is
+	// only used during execution.
+	UserDefaults map[string]string `yaml:"-"`
+	// PreviousValues holds the values of the params that are prompted for again, keyed by param name. They are offered
+	// as the defaults at the prompt. This is not saved in the config file and is only used during execution.
+	PreviousValues map[string]string `yaml:"-"`
+	// SkipHooks is true if no hooks, including validation hooks, are run for the current command. This is not saved in
+	// the config file and is only used during execution.
+	SkipHooks bool `yaml:"-"`
+	// RestrictHookEnv is true if the user requires hooks to run with a restricted environment even if the template does
+	// not ask for it. This is not saved in the config file and is only used during execution.
+	RestrictHookEnv bool `yaml:"-"`
+	// HookOutputs holds the values published by pre hooks under names that are not params of the template. They are
+	// available to templates and later hooks by name. This is not saved in the config file and is only used during
+	// execution.
+	HookOutputs map[string]string `yaml:"-"`
 	// Repository is the URL of the repository to clone. Required.
 	Repository string `yaml:"repository"`
 	// Directory is the directory at the root of the repository that holds the template data. Required.
@@ -65,6 +201,10 @@ type Template struct {
 
 	// Params is a list of parameters to prompt the user for when initializing a new repository. Optional.
 	Params []*Param `yaml:"params,omitempty"`
+	// Unmanaged is a list of parameters that were recorded locally but are no longer defined by the template. Optional.
+	// They are kept for reference only and are not passed to templates or hooks. If the template defines a parameter
+	// with the same name again, the recorded value is carried forward.
+	Unmanaged []*Param `yaml:"unmanaged,omitempty"`
 
 	// InitOnlyPaths are a list of glob paths that are only copied over during intialization. Optional. The glob paths
 	// are relative to Directory.
@@ -73,18 +213,33 @@ type Template struct {
 	// The glob paths are relative to Directory.
 	RawCopyPaths []string `yaml:"raw-copy,omitempty"`
 
-	// PreInitHookPaths are a list of paths to scripts to run before initializing the repository. Optional. The paths
-	// are relative to the repository root. The hooks are run in the order they are defined.
-	PreInitHookPaths []string `yaml:"pre-init-hooks,omitempty"`
-	// PostInitHookPaths are a list of paths to scripts to run after initializing the repository. Optional. The paths
-	// are relative to the repository root. The hooks are run in the order they are defined.
-	PostInitHookPaths []string `yaml:"post-init-hooks,omitempty"`
-	// PreUpdateHookPaths are a list of paths to scripts to run before updating the repository. Optional. The paths
-	// are relative to the repository root. The hooks are run in the order they are defined.
-	PreUpdateHookPaths []string `yaml:"pre-update-hooks,omitempty"`
-	// PostUpdateHookPaths are a list of paths to scripts to run after updating the repository. Optional. The paths
-	// are relative to the repository root. The hooks are run in the order they are defined.
-	PostUpdateHookPaths []string `yaml:"post-update-hooks,omitempty"`
+	// GoFormat is true if rendered .go files are formatted with go/format before they are written. Optional. A
+	// rendered file that is not valid Go is reported with the path of its template.
+	GoFormat bool `yaml:"go-format,omitempty"`
+	// Processors are a list of processors that rendered files are run through before they are written. Optional. Each
+	// file is run through every processor whose glob matches it, in the order they are defined. Raw copy files are not
+	// processed.
+	Processors []*Processor `yaml:"processors,omitempty"`
+
+	// HookEnv decides which environment variables are passed to hooks. Optional. By default hooks receive the full
+	// environment of stenciler.
+	HookEnv *HookEnv `yaml:"hook-env,omitempty"`
+	// PreInitHooks are a list of hooks to run before initializing the repository. Optional. The hooks are run in the
+	// order they are defined. The hooks may publish outputs to the file named by OutputFileEnv, see HookOutputs.
+	PreInitHooks []*Hook `yaml:"pre-init-hooks,omitempty"`
+	// PostInitHooks are a list of hooks to run after initializing the repository. Optional. The hooks are run in the
+	// order they are defined.
+	PostInitHooks []*Hook `yaml:"post-init-hooks,omitempty"`
+	// PreUpdateHooks are a list of hooks to run before updating the repository. Optional. The hooks are run in the
+	// order they are defined. The hooks may publish outputs to the file named by OutputFileEnv, see HookOutputs.
+	PreUpdateHooks []*Hook `yaml:"pre-update-hooks,omitempty"`
+	// PostUpdateHooks are a list of hooks to run after updating the repository. Optional. The hooks are run in the
+	// order they are defined.
+	PostUpdateHooks []*Hook `yaml:"post-update-hooks,omitempty"`
+	// OnFailureHooks are a list of hooks to run when initializing or updating the repository fails or is stopped by
+	// SIGINT or SIGTERM after it has started writing, e.g. to clean up. Optional. The hooks are run in the order they
+	// are defined.
+	OnFailureHooks []*Hook `yaml:"on-failure-hooks,omitempty"`
 }
 
 // Config holds the contents of a configuration file.
@@ -140,32 +295,38 @@ func (c *Config) Write(out io.Writer) error {
 	return err
 }
 
-// LogValue returns the slog.Value representation of the Config.
+// LogValue returns the slog.Value representation of the Config. Templates are keyed by their directory.
 func (c Config) LogValue() slog.Value {
-	templates := make([]Template, 0, len(c.Templates))
+	templates := make([]slog.Attr, 0, len(c.Templates))
 	for _, t := range c.Templates {
-		templates = append(templates, *t)
+		templates = append(templates, slog.Any(t.Directory, *t))
 	}
-	return slog.AnyValue(templates)
+	return slog.GroupValue(templates...)
 }
 
-// LogValue returns the slog.Value representation of the Template.
+// LogValue returns the slog.Value representation of the Template. Params are keyed by their name.
 func (t Template) LogValue() slog.Value {
-	params := make([]Param, 0, len(t.Params))
+	params := make([]slog.Attr, 0, len(t.Params))
 	for _, p := range t.Params {
-		params = append(params, *p)
+		params = append(params, slog.Any(p.Name, *p))
+	}
+	unmanaged := make([]slog.Attr, 0, len(t.Unmanaged))
+	for _, p := range t.Unmanaged {
+		unmanaged = append(unmanaged, slog.Any(p.Name, *p))
 	}
 
 	return slog.GroupValue(
 		slog.String("repository", t.Repository),
 		slog.String("directory", t.Directory),
-		slog.Any("params", params),
+		slog.Attr{Key: "params", Value: slog.GroupValue(params...)},
+		slog.Attr{Key: "unmanaged", Value: slog.GroupValue(unmanaged...)},
 		slog.Any("init-only", t.InitOnlyPaths),
 		slog.Any("raw-copy", t.RawCopyPaths),
-		slog.Any("pre-init-hooks", t.PreInitHookPaths),
-		slog.Any("post-init-hooks", t.PostInitHookPaths),
-		slog.Any("pre-update-hooks", t.PreUpdateHookPaths),
-		slog.Any("post-update-hooks", t.PostUpdateHookPaths),
+		slog.Any("pre-init-hooks", t.PreInitHooks),
+		slog.Any("post-init-hooks", t.PostInitHooks),
+		slog.Any("pre-update-hooks", t.PreUpdateHooks),
+		slog.Any("post-update-hooks", t.PostUpdateHooks),
+		slog.Any("on-failure-hooks", t.OnFailureHooks),
 	)
 }
 
@@ -175,49 +336,178 @@ func (p Param) LogValue() slog.Value {
 		slog.String("name", p.Name),
 		slog.String("prompt", p.Prompt),
 		slog.String("default", p.Default),
-		slog.String("validation-hook", p.ValidationHook),
-		slog.String("value", p.Value),
+		slog.Any("default-from", p.DefaultFrom),
+		slog.Any("validation-hook", p.ValidationHook),
+		slog.Bool("required", p.Required),
+		slog.String("pattern", p.Pattern),
+		slog.Int("min-length", p.MinLength),
+		slog.Int("max-length", p.MaxLength),
+		slog.Any("min", p.Min),
+		slog.Any("max", p.Max),
+		slog.Any("allowed-values", p.AllowedValues),
+		slog.Bool("secret", p.Secret),
+		slog.String("value", p.DisplayValue()),
+		slog.String("source", string(p.Source)),
 	)
 }
 
-// Validate runs the validation hook for the parameter and updates the value with the output of the hook. If there is no
-// validation hook, then the value is unchanged and the function returns no error. It expects the hook to return an
-// updated value written to standard out for the given parameter. No other output should be present on stdout. Standard
-// error content is ignored.
+// MarshalYAML implements yaml.Marshaler so that the value of a secret parameter is never written out.
+func (p Param) MarshalYAML() (any, error) {
+	type plainParam Param
+	out := plainParam(p)
+	if out.Secret {
+		out.Value = ""
+	}
+	return out, nil
+}
+
+// DisplayValue returns the value of the parameter in a form that is safe to show to the user or write to a log.
+func (p *Param) DisplayValue() string {
+	if p.Secret && len(p.Value) > 0 {
+		return redacted
+	}
+	return p.Value
+}
+
+// Validate checks the value against the declarative rules for the parameter, then runs the validation hook and updates
+// the value with the output of the hook. If there is no validation hook, then the value is unchanged. It expects the
+// hook to return an updated value written to standard out for the given parameter. No other output should be present
+// on stdout. If the value is rejected, a *ValidationError is returned. When the hook exits with InvalidValueExitCode,
+// its standard error content is used as the reason; otherwise standard error content is ignored. If the hook changes
+// the value, the source of the value becomes HookValue.
 func (p *Param) Validate(repoDir string) error {
-	if len(p.ValidationHook) == 0 {
+	return p.validate(repoDir, nil)
+}
+
+// validate validates the value of the parameter, running the validation hook with the given environment. A nil
+// environment passes the full environment of stenciler to the hook.
+func (p *Param) validate(repoDir string, env []string) error {
+	if err := p.CheckRules(); err != nil {
+		return err
+	}
+
+	if p.ValidationHook == nil {
 		return nil
 	}
-	hook := filepath.Join(repoDir, p.ValidationHook)
-	out, err := exec.Command("/bin/sh", hook, p.Name, p.Value).Output()
+	args := []string{p.Name, p.Value}
+	// Arguments are visible to other users in the process list, unlike the environment.
+	if p.Secret {
+		args = args[:1]
+		if env == nil {
+			env = os.Environ()
+		}
+		env = append(env, p.envName()+"="+p.Value)
+	}
+	var stdout, stderr bytes.Buffer
+	err := p.ValidationHook.run(interruptContext, repoDir, func(cmd *exec.Cmd) {
+		cmd.Env = env
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+	}, args...)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == InvalidValueExitCode {
+		reason := strings.TrimSpace(stderr.String())
+		if len(reason) == 0 {
+			reason = fmt.Sprintf("value %q for %s was rejected by %s", p.DisplayValue(), p.Name, p.ValidationHook)
+		}
+		return &ValidationError{Param: p.Name, Err: errors.New(reason)}
+	}
 	if err != nil {
-		return fmt.Errorf("failed to execute validation hook %s on %s with value %s: %w",
-			p.ValidationHook, p.Name, p.Value, err)
+		err = fmt.Errorf("failed to execute validation hook %s on %s with value %s: %w",
+			p.ValidationHook, p.Name, p.DisplayValue(), err)
+		if output := strings.TrimSpace(stderr.String()); len(output) > 0 {
+			slog.Debug("validation hook output", slog.String("hook", p.ValidationHook.String()),
+				slog.String("stderr", output))
+			err = fmt.Errorf("%w\n%s", err, output)
+		}
+		return err
+	}
+	value := strings.TrimSpace(stdout.String())
+	if value != p.Value {
+		p.Value = value
+		p.Source = HookValue
 	}
-	p.Value = strings.TrimSpace(string(out))
 
 	return nil
 }
 
-// ExecuteHooks executes each of the pre/post hooks in the order they were listed. If a hook exits with a non-zero exit
-// code, all execution with stop and an error will be returned.
-func (t *Template) ExecuteHooks(repoDir string, hookClass HookClass) error {
-	var hooks []string
+// Hooks returns the hooks of the given class in the order they are run.
+func (t *Template) Hooks(hookClass HookClass) ([]*Hook, error) {
 	switch hookClass {
 	case PreInitHook:
-		hooks = t.PreInitHookPaths
+		return t.PreInitHooks, nil
 	case PostInitHook:
-		hooks = t.PostInitHookPaths
+		return t.PostInitHooks, nil
 	case PreUpdateHook:
-		hooks = t.PreUpdateHookPaths
+		return t.PreUpdateHooks, nil
 	case PostUpdateHook:
-		hooks = t.PostUpdateHookPaths
+		return t.PostUpdateHooks, nil
+	case OnFailureHook:
+		return t.OnFailureHooks, nil
 	default:
-		return fmt.Errorf("unknown hook class %d", hookClass)
+		return nil, fmt.Errorf("unknown hook class %d", hookClass)
+	}
+}
+
+// ValidateParam validates the value of the parameter. Only the declarative rules are checked if the hooks of the
+// template are skipped. Otherwise the validation hook receives the same environment as the other hooks of the template,
+// so it can check the value against the values of the parameters answered before it, along with a JSON context file.
+func (t *Template) ValidateParam(p *Param, repoDir string) error {
+	if t.SkipHooks {
+		return p.CheckRules()
+	}
+	if p.ValidationHook == nil {
+		return p.Validate(repoDir)
+	}
+
+	contextFile, err := t.writeValidationContext(p)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(contextFile)
+
+	env := append(t.hookEnviron(), ContextFileEnv+"="+contextFile)
+	return p.validate(repoDir, env)
+}
+
+// ExecuteHooks executes each of the pre/post hooks in the order they were listed. If a hook exits with a non-zero exit
+// code, all execution with stop and an error will be returned. The output of the hooks is written to stderr.
+func (t *Template) ExecuteHooks(repoDir string, hookClass HookClass) error {
+	return t.ExecuteHooksWithOut(repoDir, hookClass, os.Stderr)
+}
+
+// ExecuteHooksWithOut executes each of the pre/post hooks in the order they were listed. If a hook fails, its failure
+// policy decides whether all execution stops and an error is returned, a warning is written to out and execution
+// continues, or the failure is ignored. A hook stopped by Interrupt always stops execution. No hooks are run if
+// SkipHooks is set. Each line the hooks write to stdout or stderr is written to out as it is produced, prefixed with
+// the name of the hook.
+func (t *Template) ExecuteHooksWithOut(repoDir string, hookClass HookClass, out io.Writer) error {
+	hooks, err := t.Hooks(hookClass)
+	if err != nil {
+		return err
+	}
+	if t.SkipHooks {
+		slog.Debug("skipping hooks", slog.String("class", hookClass.String()), slog.Int("count", len(hooks)))
+		return nil
 	}
 
 	for _, hook := range hooks {
-		if err := t.executeHook(filepath.Join(repoDir, hook)); err != nil {
+		err := t.executeHook(repoDir, hookClass, hook, out)
+		if err == nil {
+			continue
+		}
+		// A hook stopped by a signal always stops execution, as the user asked for stenciler to stop.
+		var interrupted *InterruptedError
+		if errors.As(err, &interrupted) {
+			return err
+		}
+		switch hook.FailurePolicy {
+		case WarnOnFailure:
+			slog.Debug("hook failed, continuing", slog.String("hook", hook.String()), slog.Any("error", err))
+			fmt.Fprintf(out, "warning: %s\n", err)
+		case IgnoreFailure:
+			slog.Debug("ignoring hook failure", slog.String("hook", hook.String()), slog.Any("error", err))
+		default:
 			return err
 		}
 	}
@@ -225,19 +515,43 @@ func (t *Template) ExecuteHooks(repoDir string, hookClass HookClass) error {
 	return nil
 }
 
-func (t *Template) executeHook(hook string) error {
-	cmd := exec.Command("/bin/sh", hook)
+func (t *Template) executeHook(repoDir string, hookClass HookClass, hook *Hook, out io.Writer) error {
+	env := t.hookEnviron()
 
-	env := os.Environ()
-	for _, p := range t.Params {
-		name := strcase.ToScreamingSnake(p.Name)
-		env = append(env, fmt.Sprintf("STENCILER_%s=%s", name, p.Value))
+	var outputFile string
+	if publishesOutputs(hookClass) {
+		f, err := os.CreateTemp("", "stenciler-output-*")
+		if err != nil {
+			return fmt.Errorf("failed to create output file for hook %s: %w", hook, err)
+		}
+		outputFile = f.Name()
+		f.Close()
+		defer os.Remove(outputFile)
+		env = append(env, OutputFileEnv+"="+outputFile)
 	}
-	cmd.Env = env
 
-	if err := cmd.Run(); err != nil {
+	// The on-failure hooks are not stopped by Interrupt as they are run to clean up after it.
+	ctx := interruptContext
+	if hookClass == OnFailureHook {
+		ctx = context.Background()
+	}
+
+	// Using the same writer for stdout and stderr keeps the lines in the order the hook wrote them.
+	output := newHookOutputWriter(hook, out)
+	err := hook.run(ctx, repoDir, func(cmd *exec.Cmd) {
+		cmd.Env = env
+		cmd.Stdout = output
+		cmd.Stderr = output
+	})
+	if flushErr := output.Flush(); flushErr != nil && err == nil {
+		err = flushErr
+	}
+	if err != nil {
 		return fmt.Errorf("failed to execute hook %s: %w", hook, err)
 	}
 
+	if len(outputFile) > 0 {
+		return t.readHookOutputs(repoDir, hook, outputFile)
+	}
 	return nil
 }
@@ -1,6 +1,7 @@
 package config_test
 
 import (
+	"log/slog"
 	"os"
 	"path"
 	"strings"
@@ -30,9 +31,18 @@ func (s *ConfigTestSuite) SetupTest() {
   params:
   - name: param1
     prompt: prompt1
+    description: description1
+    help: help1
+    examples: ["example1"]
     default: mine
     validation-hook: hook1
+    required: true
+    pattern: "[a-z]+"
+    min-length: 1
+    max-length: 10
+    allowed-values: ["mine", "yours"]
     value: yours
+    source: prompted
   init-only: ["init1"]
   raw-copy: ["raw1"]
   pre-init-hooks: ["pre-init1"]
@@ -50,17 +60,26 @@ func (s *ConfigTestSuite) SetupTest() {
 					{
 						Name:           "param1",
 						Prompt:         "prompt1",
+						Description:    "description1",
+						Help:           "help1",
+						Examples:       []string{"example1"},
 						Default:        "mine",
-						ValidationHook: "hook1",
+						ValidationHook: &config.Hook{Path: "hook1"},
+						Required:       true,
+						Pattern:        "[a-z]+",
+						MinLength:      1,
+						MaxLength:      10,
+						AllowedValues:  []string{"mine", "yours"},
 						Value:          "yours",
+						Source:         config.PromptedValue,
 					},
 				},
-				InitOnlyPaths:       []string{"init1"},
-				RawCopyPaths:        []string{"raw1"},
-				PreInitHookPaths:    []string{"pre-init1"},
-				PostInitHookPaths:   []string{"post-init1"},
-				PreUpdateHookPaths:  []string{"pre-update1"},
-				PostUpdateHookPaths: []string{"post-update1"},
+				InitOnlyPaths:   []string{"init1"},
+				RawCopyPaths:    []string{"raw1"},
+				PreInitHooks:    []*config.Hook{{Path: "pre-init1"}},
+				PostInitHooks:   []*config.Hook{{Path: "post-init1"}},
+				PreUpdateHooks:  []*config.Hook{{Path: "pre-update1"}},
+				PostUpdateHooks: []*config.Hook{{Path: "post-update1"}},
 			},
 		},
 	}
@@ -80,6 +99,67 @@ func (s *ConfigTestSuite) TestWrite() {
 	s.Require().YAMLEq(s.configText, writer.String())
 }
 
+func (s *ConfigTestSuite) TestWriteOmitsSecretValues() {
+	cfg := &config.Config{
+		Templates: []*config.Template{
+			{
+				Repository: "https://github.com/rogueserenity/stenciler-test",
+				Directory:  "test",
+				Params: []*config.Param{
+					{
+						Name:   "token",
+						Prompt: "Token",
+						Secret: true,
+						Value:  "hunter2",
+					},
+				},
+			},
+		},
+	}
+	expected := `templates:
+- repository: https://github.com/rogueserenity/stenciler-test
+  directory: test
+  params:
+  - name: token
+    prompt: Token
+    secret: true
+`
+
+	writer := &strings.Builder{}
+	err := cfg.Write(writer)
+	s.Require().NoError(err)
+	s.Require().YAMLEq(expected, writer.String())
+	s.Require().Equal("hunter2", cfg.Templates[0].Params[0].Value)
+}
+
+func (s *ConfigTestSuite) TestLogValueRedactsSecretValues() {
+	cfg := &config.Config{
+		Templates: []*config.Template{
+			{
+				Directory: "test",
+				Params: []*config.Param{
+					{
+						Name:   "token",
+						Secret: true,
+						Value:  "hunter2",
+					},
+					{
+						Name:  "name",
+						Value: "serenity",
+					},
+				},
+			},
+		},
+	}
+
+	out := &strings.Builder{}
+	logger := slog.New(slog.NewJSONHandler(out, nil))
+	logger.Info("config", slog.Any("config", cfg))
+	s.Require().NotContains(out.String(), "hunter2")
+	s.Require().Contains(out.String(), "[REDACTED]")
+	s.Require().Contains(out.String(), "serenity")
+}
+
 func (s *ConfigTestSuite) TestParamValidateNoHook() {
 	param := &config.Param{
 		Name:    "test",
@@ -98,7 +178,7 @@ func (s *ConfigTestSuite) TestParamValidateMissingHook() {
 		Prompt:         "Test Prompt",
 		Default:        "default",
 		Value:          "value",
-		ValidationHook: "missing-hook",
+		ValidationHook: &config.Hook{Path: "missing-hook"},
 	}
 
 	dir := os.TempDir()
@@ -112,7 +192,7 @@ func (s *ConfigTestSuite) TestParamValidateWithHook() {
 		Prompt:         "Test Prompt",
 		Default:        "default",
 		Value:          "value",
-		ValidationHook: "valid.sh",
+		ValidationHook: &config.Hook{Path: "valid.sh"},
 	}
 
 	contents := `#!/bin/sh
@@ -123,6 +203,7 @@ func (s *ConfigTestSuite) TestParamValidateWithHook() {
 	s.Require().NoError(err)
 	_, err = f.Write([]byte(contents))
 	s.Require().NoError(err)
+	s.Require().NoError(f.Chmod(0755))
 	s.Require().NoError(f.Close())
 	defer os.Remove(path.Join(dir, "valid.sh"))
 
@@ -132,6 +213,55 @@ func (s *ConfigTestSuite) TestParamValidateWithHook() {
 	s.Require().Equal("validated_value", param.Value)
 }
 
+func (s *ConfigTestSuite) TestParamValidateWithRejectingHook() {
+	param := &config.Param{
+		Name:           "test",
+		Prompt:         "Test Prompt",
+		Value:          "value",
+		ValidationHook: &config.Hook{Path: "reject.sh"},
+	}
+
+	contents := `#!/bin/sh
+	echo "value must be uppercase" >&2
+	exit 65
+`
+	dir := os.TempDir()
+	err := os.WriteFile(path.Join(dir, "reject.sh"), []byte(contents), 0755)
+	s.Require().NoError(err)
+	defer os.Remove(path.Join(dir, "reject.sh"))
+
+	err = param.Validate(dir)
+	var validationErr *config.ValidationError
+	s.Require().ErrorAs(err, &validationErr)
+	s.Require().Equal("test", validationErr.Param)
+	s.Require().EqualError(err, "value must be uppercase")
+	s.Require().Equal("value", param.Value)
+}
+
+func (s *ConfigTestSuite) TestParamValidateWithFailingHook() {
+	param := &config.Param{
+		Name:           "test",
+		Prompt:         "Test Prompt",
+		Value:          "value",
+		ValidationHook: &config.Hook{Path: "fail.sh"},
+	}
+
+	contents := `#!/bin/sh
+	echo "something broke" >&2
+	exit 1
+`
+	dir := os.TempDir()
+	err := os.WriteFile(path.Join(dir, "fail.sh"), []byte(contents), 0755)
+	s.Require().NoError(err)
+	defer os.Remove(path.Join(dir, "fail.sh"))
+
+	err = param.Validate(dir)
+	var validationErr *config.ValidationError
+	s.Require().ErrorContains(err, "failed to execute validation hook fail.sh on test with value value:")
+	s.Require().ErrorContains(err, "something broke")
+	s.Require().NotErrorAs(err, &validationErr)
+}
+
 func (s *ConfigTestSuite) TestExecuteHooksInvalidClass() {
 	template := &config.Template{
 		Repository: faker.URL(),
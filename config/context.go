@@ -0,0 +1,60 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ContextFileEnv is the environment variable that holds the path of the JSON context file given to validation hooks.
+const ContextFileEnv = BuiltInsEnvPrefix + "CONTEXT_FILE"
+
+// ValidationContext is the content of the JSON context file given to validation hooks. The file is removed once the
+// hook exits.
+type ValidationContext struct {
+	// Param is the name of the parameter being validated.
+	Param string `json:"param"`
+	// Value is the value being validated.
+	Value string `json:"value"`
+	// Params holds the values of the parameters that have been answered so far, keyed by name. It includes the
+	// parameter being validated.
+	Params map[string]string `json:"params"`
+	// BuiltIns holds the built-in values keyed by the names templates use for them, e.g. TargetDir.
+	BuiltIns map[string]string `json:"builtins,omitempty"`
+}
+
+// writeValidationContext writes the context for the validation hook of the parameter to a temporary file and returns
+// its path. The caller is responsible for removing the file.
+func (t *Template) writeValidationContext(p *Param) (string, error) {
+	context := ValidationContext{
+		Param:  p.Name,
+		Value:  p.Value,
+		Params: make(map[string]string),
+	}
+	for _, param := range t.Params {
+		if len(param.Value) > 0 {
+			context.Params[param.Name] = param.Value
+		}
+	}
+	if t.BuiltIns != nil {
+		context.BuiltIns = t.BuiltIns.Map()
+	}
+
+	data, err := json.MarshalIndent(context, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal validation context for %s: %w", p.Name, err)
+	}
+	f, err := os.CreateTemp("", "stenciler-context-*.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to create validation context for %s: %w", p.Name, err)
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write validation context for %s: %w", p.Name, err)
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// GitDefaultSource takes the default from a git config key, e.g. git:user.email.
+	GitDefaultSource = "git"
+	// EnvDefaultSource takes the default from an environment variable, e.g. env:USER.
+	EnvDefaultSource = "env"
+	// TargetDirDefaultSource takes the default from the base name of the directory being initialized.
+	TargetDirDefaultSource = "target-dir"
+)
+
+// DefaultSource is a parsed entry of Param.DefaultFrom.
+type DefaultSource struct {
+	// Kind is one of GitDefaultSource, EnvDefaultSource or TargetDirDefaultSource.
+	Kind string
+	// Key is the git config key or environment variable name. It is empty for TargetDirDefaultSource.
+	Key string
+}
+
+// ParseDefaultSource parses an entry of Param.DefaultFrom in the form kind[:key].
+func ParseDefaultSource(source string) (DefaultSource, error) {
+	kind, key, _ := strings.Cut(source, ":")
+	switch kind {
+	case GitDefaultSource, EnvDefaultSource:
+		if len(key) == 0 {
+			return DefaultSource{}, fmt.Errorf("default source %s requires a key", source)
+		}
+	case TargetDirDefaultSource:
+		if len(key) > 0 {
+			return DefaultSource{}, fmt.Errorf("default source %s does not take a key", source)
+		}
+	default:
+		return DefaultSource{}, fmt.Errorf("unknown default source %s", source)
+	}
+	return DefaultSource{Kind: kind, Key: key}, nil
+}
+
+// validateDefaultSources ensures that every entry of DefaultFrom can be parsed.
+func (p *Param) validateDefaultSources() error {
+	var errs []error
+	for _, source := range p.DefaultFrom {
+		if _, err := ParseDefaultSource(source); err != nil {
+			errs = append(errs, fmt.Errorf("param %s has an invalid default source: %w", p.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
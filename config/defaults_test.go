@@ -0,0 +1,58 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rogueserenity/stenciler/config"
+)
+
+type DefaultsTestSuite struct {
+	suite.Suite
+}
+
+func TestDefaultsTestSuite(t *testing.T) {
+	suite.Run(t, new(DefaultsTestSuite))
+}
+
+func (s *DefaultsTestSuite) TestParseDefaultSource() {
+	source, err := config.ParseDefaultSource("git:user.email")
+	s.Require().NoError(err)
+	s.Require().Equal(config.DefaultSource{Kind: config.GitDefaultSource, Key: "user.email"}, source)
+
+	source, err = config.ParseDefaultSource("env:USER")
+	s.Require().NoError(err)
+	s.Require().Equal(config.DefaultSource{Kind: config.EnvDefaultSource, Key: "USER"}, source)
+
+	source, err = config.ParseDefaultSource("target-dir")
+	s.Require().NoError(err)
+	s.Require().Equal(config.DefaultSource{Kind: config.TargetDirDefaultSource}, source)
+}
+
+func (s *DefaultsTestSuite) TestParseInvalidDefaultSource() {
+	_, err := config.ParseDefaultSource("git")
+	s.Require().EqualError(err, "default source git requires a key")
+
+	_, err = config.ParseDefaultSource("env:")
+	s.Require().EqualError(err, "default source env: requires a key")
+
+	_, err = config.ParseDefaultSource("target-dir:foo")
+	s.Require().EqualError(err, "default source target-dir:foo does not take a key")
+
+	_, err = config.ParseDefaultSource("file:foo")
+	s.Require().EqualError(err, "unknown default source file:foo")
+}
+
+func (s *DefaultsTestSuite) TestValidateDefaultSources() {
+	template := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:        "author",
+				DefaultFrom: []string{"git:user.name", "nope"},
+			},
+		},
+	}
+	err := template.Validate("test-repo")
+	s.Require().EqualError(err, "param author has an invalid default source: unknown default source nope")
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/iancoleman/strcase"
+)
+
+// defaultPath is the PATH given to hooks in a restricted environment when stenciler itself has no PATH.
+const defaultPath = "/usr/local/bin:/usr/bin:/bin"
+
+// HookEnv decides which environment variables are passed to hooks.
+type HookEnv struct {
+	// Restricted is true if hooks only receive PATH, HOME, the variables listed in Pass and the variables set by
+	// stenciler instead of the full environment of stenciler. Optional.
+	Restricted bool `yaml:"restricted,omitempty"`
+	// Pass is a list of names of variables that are passed from the environment of stenciler to hooks in a restricted
+	// environment, e.g. GOPROXY or SSH_AUTH_SOCK. Optional.
+	Pass []string `yaml:"pass,omitempty"`
+}
+
+// RestrictedHookEnv returns true if hooks are run with a restricted environment, either because the template asks for
+// it or because the user requires it.
+func (t *Template) RestrictedHookEnv() bool {
+	return t.RestrictHookEnv || (t.HookEnv != nil && t.HookEnv.Restricted)
+}
+
+// PassedHookEnv returns the names of the variables passed from the environment of stenciler to hooks in a restricted
+// environment, in addition to PATH and HOME.
+func (t *Template) PassedHookEnv() []string {
+	if t.HookEnv == nil {
+		return nil
+	}
+	return t.HookEnv.Pass
+}
+
+// hookEnviron returns the environment for the hooks of the template. It is either the full environment of stenciler
+// or, if restricted, only PATH, HOME and the passed variables. In both cases the value of each parameter is added as
+// STENCILER_<NAME> along with the built-in values.
+func (t *Template) hookEnviron() []string {
+	var env []string
+	if t.RestrictedHookEnv() {
+		env = restrictedEnviron(t.PassedHookEnv())
+	} else {
+		env = os.Environ()
+	}
+
+	for _, p := range t.Params {
+		env = append(env, p.envName()+"="+p.Value)
+	}
+	for _, name := range slices.Sorted(maps.Keys(t.HookOutputs)) {
+		env = append(env, fmt.Sprintf("STENCILER_%s=%s", strcase.ToScreamingSnake(name), t.HookOutputs[name]))
+	}
+	if t.BuiltIns != nil {
+		env = append(env, t.BuiltIns.Environ()...)
+	}
+	return env
+}
+
+// envName returns the name of the environment variable that holds the value of the parameter for hooks.
+func (p *Param) envName() string {
+	return "STENCILER_" + strcase.ToScreamingSnake(p.Name)
+}
+
+// restrictedEnviron returns PATH and HOME along with the passed variables that are set in the environment of
+// stenciler.
+func restrictedEnviron(pass []string) []string {
+	path := os.Getenv("PATH")
+	if len(path) == 0 {
+		path = defaultPath
+	}
+	env := []string{"PATH=" + path}
+	if home, err := os.UserHomeDir(); err == nil {
+		env = append(env, "HOME="+home)
+	}
+
+	for _, name := range pass {
+		if name == "PATH" || name == "HOME" {
+			continue
+		}
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return slices.Clip(env)
+}
@@ -0,0 +1,177 @@
+package config_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rogueserenity/stenciler/config"
+)
+
+type HookEnvTestSuite struct {
+	suite.Suite
+
+	repoDir  string
+	template *config.Template
+}
+
+func TestHookEnvTestSuite(t *testing.T) {
+	suite.Run(t, new(HookEnvTestSuite))
+}
+
+func (s *HookEnvTestSuite) SetupTest() {
+	s.repoDir = s.T().TempDir()
+	s.T().Setenv("HOME", "/home/mal")
+	s.T().Setenv("STENCILER_TEST_TOKEN", "hunter2")
+	s.T().Setenv("STENCILER_TEST_PROXY", "https://proxy.example")
+
+	s.template = &config.Template{
+		Params: []*config.Param{
+			{
+				Name:  "ship",
+				Value: "Serenity",
+			},
+		},
+		PostInitHooks: []*config.Hook{
+			{Run: "env | sort"},
+		},
+	}
+}
+
+// hookEnv runs the post init hooks of the template and returns the environment they saw.
+func (s *HookEnvTestSuite) hookEnv() map[string]string {
+	out := &strings.Builder{}
+	err := s.template.ExecuteHooksWithOut(s.repoDir, config.PostInitHook, out)
+	s.Require().NoError(err)
+
+	env := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		name, value, _ := strings.Cut(strings.TrimPrefix(line, "[env | sort] "), "=")
+		env[name] = value
+	}
+	return env
+}
+
+func (s *HookEnvTestSuite) TestFullEnvironment() {
+	env := s.hookEnv()
+	s.Require().Equal("hunter2", env["STENCILER_TEST_TOKEN"])
+	s.Require().Equal("Serenity", env["STENCILER_SHIP"])
+}
+
+func (s *HookEnvTestSuite) TestRestrictedByTemplate() {
+	s.template.HookEnv = &config.HookEnv{
+		Restricted: true,
+		Pass:       []string{"STENCILER_TEST_PROXY", "STENCILER_TEST_UNSET"},
+	}
+
+	env := s.hookEnv()
+	s.Require().Equal(os.Getenv("PATH"), env["PATH"])
+	s.Require().Equal("/home/mal", env["HOME"])
+	s.Require().Equal("https://proxy.example", env["STENCILER_TEST_PROXY"])
+	s.Require().Equal("Serenity", env["STENCILER_SHIP"])
+	s.Require().NotContains(env, "STENCILER_TEST_TOKEN")
+	s.Require().NotContains(env, "STENCILER_TEST_UNSET")
+}
+
+func (s *HookEnvTestSuite) TestRestrictedByUser() {
+	s.template.RestrictHookEnv = true
+
+	env := s.hookEnv()
+	s.Require().Equal("/home/mal", env["HOME"])
+	s.Require().NotContains(env, "STENCILER_TEST_TOKEN")
+	s.Require().NotContains(env, "STENCILER_TEST_PROXY")
+	s.Require().True(s.template.RestrictedHookEnv())
+}
+
+func (s *HookEnvTestSuite) TestRestrictedValidationHook() {
+	err := os.WriteFile(filepath.Join(s.repoDir, "token.sh"), []byte("#!/bin/sh\necho \"$STENCILER_TEST_TOKEN\"\n"),
+		0755)
+	s.Require().NoError(err)
+	param := &config.Param{
+		Name:           "token",
+		Value:          "value",
+		ValidationHook: &config.Hook{Path: "token.sh"},
+	}
+
+	err = s.template.ValidateParam(param, s.repoDir)
+	s.Require().NoError(err)
+	s.Require().Equal("hunter2", param.Value)
+
+	s.template.HookEnv = &config.HookEnv{Restricted: true}
+	param.Value = "value"
+	err = s.template.ValidateParam(param, s.repoDir)
+	s.Require().NoError(err)
+	s.Require().Empty(param.Value)
+}
+
+func (s *HookEnvTestSuite) TestSecretValidationHook() {
+	param := &config.Param{
+		Name:   "api_token",
+		Value:  "hunter2",
+		Secret: true,
+		ValidationHook: &config.Hook{
+			Run: `[ $# -eq 1 ] && [ "$1" = api_token ] && [ "$STENCILER_API_TOKEN" = hunter2 ] || exit 65
+echo "$STENCILER_API_TOKEN"`,
+		},
+	}
+
+	err := s.template.ValidateParam(param, s.repoDir)
+	s.Require().NoError(err)
+
+	s.template.HookEnv = &config.HookEnv{Restricted: true}
+	err = s.template.ValidateParam(param, s.repoDir)
+	s.Require().NoError(err)
+
+	err = param.Validate(s.repoDir)
+	s.Require().NoError(err)
+	s.Require().Equal("hunter2", param.Value)
+}
+
+func (s *HookEnvTestSuite) TestValidationHookParams() {
+	param := &config.Param{
+		Name:           "module",
+		Value:          "example.com/acme/widgets",
+		ValidationHook: &config.Hook{Run: `case "$2" in */"$STENCILER_SHIP"/*) echo ok ;; *) exit 65 ;; esac`},
+	}
+	s.template.Params = append(s.template.Params, param)
+
+	err := s.template.ValidateParam(param, s.repoDir)
+	var validationErr *config.ValidationError
+	s.Require().ErrorAs(err, &validationErr)
+
+	param.Value = "example.com/Serenity/widgets"
+	err = s.template.ValidateParam(param, s.repoDir)
+	s.Require().NoError(err)
+	s.Require().Equal("ok", param.Value)
+}
+
+func (s *HookEnvTestSuite) TestValidationContextFile() {
+	param := &config.Param{
+		Name:           "crew",
+		Value:          "Mal",
+		ValidationHook: &config.Hook{Run: `cat "$` + config.ContextFileEnv + `"`},
+	}
+	s.template.Params = append(s.template.Params, param, &config.Param{Name: "captain"})
+	s.template.BuiltIns = &config.BuiltIns{TargetDir: "firefly", Mode: config.InitMode}
+
+	err := s.template.ValidateParam(param, s.repoDir)
+	s.Require().NoError(err)
+
+	var context config.ValidationContext
+	err = json.Unmarshal([]byte(param.Value), &context)
+	s.Require().NoError(err)
+	s.Require().Equal("crew", context.Param)
+	s.Require().Equal("Mal", context.Value)
+	s.Require().Equal(map[string]string{"ship": "Serenity", "crew": "Mal"}, context.Params)
+	s.Require().Equal("firefly", context.BuiltIns["TargetDir"])
+	s.Require().Equal(config.InitMode, context.BuiltIns["Mode"])
+
+	param.ValidationHook = &config.Hook{Run: `echo "$` + config.ContextFileEnv + `"`}
+	err = s.template.ValidateParam(param, s.repoDir)
+	s.Require().NoError(err)
+	s.Require().NoFileExists(param.Value)
+}
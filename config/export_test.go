@@ -0,0 +1,8 @@
+package config
+
+import "context"
+
+// ResetInterrupt undoes Interrupt so that tests can interrupt hooks more than once.
+func ResetInterrupt() {
+	interruptContext, cancelInterruptContext = context.WithCancelCause(context.Background())
+}
@@ -0,0 +1,300 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// defaultShell is used to run inline hook commands and hooks that cannot be executed directly, such as a script
+	// without a shebang.
+	defaultShell = "/bin/sh"
+	// hookWaitDelay is how long a hook is given to exit after it is signalled before it is killed.
+	hookWaitDelay = 5 * time.Second
+	// maxHookDisplayLength is the number of characters of an inline command shown for a hook without a name.
+	maxHookDisplayLength = 60
+)
+
+// runningHooks is read locked while a hook is running so that WaitForHooks can wait for them to exit.
+var runningHooks sync.RWMutex
+
+// WaitForHooks waits for any running hooks to exit. Hooks that start while waiting are blocked until it returns. It is
+// used to let hooks that were forwarded a signal exit before stenciler itself exits.
+func WaitForHooks() {
+	runningHooks.Lock()
+	defer runningHooks.Unlock()
+}
+
+// FailurePolicy decides what happens when a hook fails.
+type FailurePolicy string
+
+const (
+	// FailOnFailure stops all execution and returns the error. This is the default.
+	FailOnFailure FailurePolicy = "fail"
+	// WarnOnFailure writes a warning and continues with the next hook.
+	WarnOnFailure FailurePolicy = "warn"
+	// IgnoreFailure continues with the next hook without a warning.
+	IgnoreFailure FailurePolicy = "ignore"
+)
+
+// Hook is a script or inline command that is run by stenciler. In the config file a hook is either the path to the
+// script or a mapping with either the path or the command and their options.
+type Hook struct {
+	// Name is the name the hook is shown under in output, prompts and errors. Optional. If not set, the path to the
+	// script or the first line of the inline command is shown.
+	Name string `yaml:"name,omitempty"`
+	// Path is the path to the script to run. Either Path or Run is required. The path is relative to the repository
+	// root.
+	Path string `yaml:"path,omitempty"`
+	// Interpreter is the command used to run the script, e.g. python3 or "bash -e". Optional. The path to the script is
+	// passed as the final argument to the interpreter. If not set, the script is executed directly so that its shebang
+	// or binary format decides how it is run. It is only used with Path.
+	Interpreter string `yaml:"interpreter,omitempty"`
+	// Run is an inline command to run, e.g. "go mod tidy". Either Path or Run is required.
+	Run string `yaml:"run,omitempty"`
+	// Shell is the shell used to run the inline command, e.g. "bash -eu". Optional. The command is passed to the shell
+	// with -c. Defaults to /bin/sh. It is only used with Run.
+	Shell string `yaml:"shell,omitempty"`
+	// Env holds additional environment variables for the hook. Optional. They take precedence over the variables set
+	// by stenciler.
+	Env map[string]string `yaml:"env,omitempty"`
+	// WorkingDir is the directory the hook is run in. Optional. It must be a relative path within the directory being
+	// initialized or updated, which is also the default.
+	WorkingDir string `yaml:"working-dir,omitempty"`
+	// Timeout is how long the hook may run before it is stopped, e.g. 30s or 5m. Optional. There is no limit if it is
+	// not set.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// FailurePolicy decides what happens when the hook fails, one of fail, warn or ignore. Optional. Defaults to fail.
+	// It does not apply to validation hooks or to a hook stopped because stenciler received a signal.
+	FailurePolicy FailurePolicy `yaml:"failure-policy,omitempty"`
+}
+
+// plainHook has the same fields as Hook without its YAML methods.
+type plainHook Hook
+
+// UnmarshalYAML reads a hook from either the path to the script or a mapping with the path or command and their
+// options.
+func (h *Hook) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		return value.Decode(&h.Path)
+	}
+	return value.Decode((*plainHook)(h))
+}
+
+// MarshalYAML writes a hook with only a path as the path to its script.
+func (h *Hook) MarshalYAML() (any, error) {
+	if h.isPathOnly() {
+		return h.Path, nil
+	}
+	return (*plainHook)(h), nil
+}
+
+func (h *Hook) isPathOnly() bool {
+	return len(h.Name) == 0 && len(h.Interpreter) == 0 && len(h.Run) == 0 && len(h.Shell) == 0 && len(h.Env) == 0 &&
+		len(h.WorkingDir) == 0 && h.Timeout == 0 && len(h.FailurePolicy) == 0
+}
+
+// String returns the name of the hook for display. It is Name if set, otherwise the first line of the inline command,
+// shortened if needed, or the path to the script preceded by the interpreter if one is set.
+func (h *Hook) String() string {
+	switch {
+	case len(h.Name) > 0:
+		return h.Name
+	case len(h.Run) > 0:
+		return shortenCommand(h.Run)
+	case len(h.Interpreter) > 0:
+		return h.Interpreter + " " + h.Path
+	default:
+		return h.Path
+	}
+}
+
+// shortenCommand returns the first line of the command, cut to maxHookDisplayLength characters. An ellipsis marks a
+// command that was cut or has more lines.
+func shortenCommand(command string) string {
+	command = strings.TrimSpace(command)
+	line, rest, _ := strings.Cut(command, "\n")
+	line = strings.TrimSpace(line)
+	shortened := len(rest) > 0
+	if runes := []rune(line); len(runes) > maxHookDisplayLength {
+		line = strings.TrimSpace(string(runes[:maxHookDisplayLength]))
+		shortened = true
+	}
+	if shortened {
+		line += "..."
+	}
+	return line
+}
+
+// validate ensures the hook has either a path or an inline command with only the options that apply to it. A script
+// must exist and, without an interpreter, must also be executable.
+func (h *Hook) validate(repoPath string) error {
+	switch {
+	case h.Timeout < 0:
+		return fmt.Errorf("hook %s has a negative timeout", h)
+	case !slices.Contains([]FailurePolicy{"", FailOnFailure, WarnOnFailure, IgnoreFailure}, h.FailurePolicy):
+		return fmt.Errorf("hook %s has an unknown failure-policy %s", h, h.FailurePolicy)
+	case len(h.WorkingDir) > 0 && !filepath.IsLocal(h.WorkingDir):
+		return fmt.Errorf("hook %s has a working-dir outside of the target directory", h)
+	case len(h.Shell) > 0 && len(strings.Fields(h.Shell)) == 0:
+		return fmt.Errorf("hook %s has a blank shell", h)
+	case len(h.Interpreter) > 0 && len(strings.Fields(h.Interpreter)) == 0:
+		return fmt.Errorf("hook %s has a blank interpreter", h.Path)
+	case len(h.Path) > 0 && len(h.Run) > 0:
+		return fmt.Errorf("hook %s sets both a path and a run command", h.Path)
+	case len(h.Run) > 0:
+		if len(h.Interpreter) > 0 {
+			return fmt.Errorf("hook %q sets an interpreter for a run command, use shell instead", h)
+		}
+		return nil
+	case len(h.Path) == 0:
+		return errors.New("hook sets neither a path nor a run command")
+	case len(h.Shell) > 0:
+		return fmt.Errorf("hook %s sets a shell for a script, use interpreter instead", h.Path)
+	}
+
+	info, err := os.Stat(filepath.Join(repoPath, h.Path))
+	if err != nil {
+		return fmt.Errorf("hook %s does not exist", h.Path)
+	}
+
+	mode := info.Mode()
+	if !mode.IsRegular() {
+		return fmt.Errorf("hook %s is not a regular file", h.Path)
+	}
+	if len(h.Interpreter) == 0 && mode.Perm()&0111 == 0 {
+		return fmt.Errorf("hook %s is not executable", h.Path)
+	}
+
+	return nil
+}
+
+// run runs the hook with the given arguments. The hook is stopped if ctx is cancelled. The command is passed to
+// configure before it is run so that the caller can set its environment and output. The environment and working
+// directory of the hook are applied afterwards. A script without an interpreter that cannot be executed directly is run
+// with /bin/sh instead.
+func (h *Hook) run(ctx context.Context, repoDir string, configure func(*exec.Cmd), args ...string) error {
+	if len(h.Run) > 0 {
+		shell := h.Shell
+		if len(shell) == 0 {
+			shell = defaultShell
+		}
+		name, shellArgs, err := splitCommand(shell)
+		if err != nil {
+			return fmt.Errorf("invalid shell for hook %s: %w", h, err)
+		}
+		// The first argument after the command becomes $0, so the hook arguments start at $1 as they do for a script.
+		shellArgs = append(shellArgs, "-c", h.Run, "stenciler-hook")
+		return h.runCommand(ctx, configure, name, append(shellArgs, args...)...)
+	}
+
+	// The path is never split, so it may contain spaces.
+	path, err := filepath.Abs(filepath.Join(repoDir, h.Path))
+	if err != nil {
+		return err
+	}
+	if len(h.Interpreter) > 0 {
+		name, interpreterArgs, err := splitCommand(h.Interpreter)
+		if err != nil {
+			return fmt.Errorf("invalid interpreter for hook %s: %w", h, err)
+		}
+		interpreterArgs = append(interpreterArgs, path)
+		return h.runCommand(ctx, configure, name, append(interpreterArgs, args...)...)
+	}
+
+	err = h.runCommand(ctx, configure, path, args...)
+	if !errors.Is(err, syscall.ENOEXEC) {
+		return err
+	}
+	return h.runCommand(ctx, configure, defaultShell, append([]string{path}, args...)...)
+}
+
+// splitCommand splits a command such as "bash -eu" into its name and arguments.
+func splitCommand(command string) (string, []string, error) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return "", nil, errors.New("command is blank")
+	}
+	return fields[0], fields[1:], nil
+}
+
+// runCommand runs the named command with the given arguments. It is run in its own process group. If ctx is cancelled
+// by Interrupt, the process group is sent the signal stenciler received, and if the hook has a timeout, the process
+// group is sent SIGTERM once it expires. Either way the hook is killed if it has not exited after hookWaitDelay.
+func (h *Hook) runCommand(ctx context.Context, configure func(*exec.Cmd), name string, args ...string) error {
+	if err := ctx.Err(); err != nil {
+		return context.Cause(ctx)
+	}
+	if h.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error {
+		sig, ok := interruptSignal(ctx)
+		if !ok {
+			sig = syscall.SIGTERM
+		}
+		slog.Debug("signalling hook", slog.String("hook", h.String()), slog.String("signal", sig.String()))
+		return signalProcessGroup(cmd, sig)
+	}
+	cmd.WaitDelay = hookWaitDelay
+	configure(cmd)
+
+	if len(h.Env) > 0 {
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		for _, name := range slices.Sorted(maps.Keys(h.Env)) {
+			cmd.Env = append(cmd.Env, name+"="+h.Env[name])
+		}
+	}
+	if len(h.WorkingDir) > 0 {
+		cmd.Dir = h.WorkingDir
+	}
+
+	runningHooks.RLock()
+	defer runningHooks.RUnlock()
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	trackHookCmd(cmd)
+	err := cmd.Wait()
+	untrackHookCmd(cmd)
+
+	if ctx.Err() == nil {
+		return err
+	}
+	// Anything the hook started that outlived it is killed along with it.
+	if killErr := signalProcessGroup(cmd, syscall.SIGKILL); killErr != nil {
+		slog.Debug("unable to kill hook process group", slog.String("hook", h.String()), slog.Any("error", killErr))
+	}
+	if _, ok := interruptSignal(ctx); ok {
+		// Wait reports a hook that exited successfully after it was signalled as cancelled.
+		if err == nil || errors.Is(err, context.Canceled) {
+			return context.Cause(ctx)
+		}
+		return fmt.Errorf("%w: %w", context.Cause(ctx), err)
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s: %w", h.Timeout, err)
+	}
+	return err
+}
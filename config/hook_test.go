@@ -0,0 +1,387 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+
+	"github.com/rogueserenity/stenciler/config"
+)
+
+type HookTestSuite struct {
+	suite.Suite
+
+	repoDir string
+}
+
+func TestHookTestSuite(t *testing.T) {
+	suite.Run(t, new(HookTestSuite))
+}
+
+func (s *HookTestSuite) SetupTest() {
+	s.repoDir = s.T().TempDir()
+}
+
+func (s *HookTestSuite) writeHook(name, contents string, perm os.FileMode) {
+	err := os.WriteFile(filepath.Join(s.repoDir, name), []byte(contents), perm)
+	s.Require().NoError(err)
+}
+
+func (s *HookTestSuite) TestYAMLRoundTrip() {
+	hooksText := `- hooks/one.sh
+- path: hooks/two.py
+  interpreter: python3 -u
+`
+	var hooks []*config.Hook
+	err := yaml.Unmarshal([]byte(hooksText), &hooks)
+	s.Require().NoError(err)
+	s.Require().Equal([]*config.Hook{
+		{Path: "hooks/one.sh"},
+		{Path: "hooks/two.py", Interpreter: "python3 -u"},
+	}, hooks)
+
+	out, err := yaml.Marshal(hooks)
+	s.Require().NoError(err)
+	s.Require().YAMLEq(hooksText, string(out))
+}
+
+func (s *HookTestSuite) TestYAMLInlineHook() {
+	hooksText := `- run: go mod tidy
+  shell: bash -eu
+  env:
+    GOFLAGS: -mod=mod
+  working-dir: api
+  timeout: 1m30s
+  failure-policy: warn
+`
+	var hooks []*config.Hook
+	err := yaml.Unmarshal([]byte(hooksText), &hooks)
+	s.Require().NoError(err)
+	s.Require().Equal([]*config.Hook{
+		{
+			Run:           "go mod tidy",
+			Shell:         "bash -eu",
+			Env:           map[string]string{"GOFLAGS": "-mod=mod"},
+			WorkingDir:    "api",
+			Timeout:       90 * time.Second,
+			FailurePolicy: config.WarnOnFailure,
+		},
+	}, hooks)
+
+	out, err := yaml.Marshal(hooks)
+	s.Require().NoError(err)
+	s.Require().YAMLEq(hooksText, string(out))
+}
+
+func (s *HookTestSuite) TestString() {
+	s.Require().Equal("hooks/one.sh", (&config.Hook{Path: "hooks/one.sh"}).String())
+	s.Require().Equal("python3 -u hooks/two.py", (&config.Hook{Path: "hooks/two.py", Interpreter: "python3 -u"}).String())
+	s.Require().Equal("git init", (&config.Hook{Run: "git init"}).String())
+	s.Require().Equal("echo one...", (&config.Hook{Run: "echo one\necho two\n"}).String())
+	long := "echo " + strings.Repeat("a", 70)
+	s.Require().Equal(long[:60]+"...", (&config.Hook{Run: long}).String())
+	s.Require().Equal("tidy", (&config.Hook{Name: "tidy", Run: "go mod tidy\ngo fmt ./..."}).String())
+	s.Require().Equal("lint", (&config.Hook{Name: "lint", Path: "hooks/lint.sh"}).String())
+}
+
+func (s *HookTestSuite) TestMultilineHookOutputPrefix() {
+	template := &config.Template{
+		PostInitHooks: []*config.Hook{
+			{Run: "echo one\necho two\n"},
+			{Name: "greet", Run: "echo hi\n"},
+		},
+	}
+
+	out := &strings.Builder{}
+	err := template.ExecuteHooksWithOut(s.repoDir, config.PostInitHook, out)
+	s.Require().NoError(err)
+	s.Require().Equal("[echo one...] one\n[echo one...] two\n[greet] hi\n", out.String())
+}
+
+func (s *HookTestSuite) TestValidationHookHonorsShebang() {
+	s.writeHook("echo.sh", "#!/bin/echo\n", 0755)
+	param := &config.Param{
+		Name:           "test",
+		Value:          "value",
+		ValidationHook: &config.Hook{Path: "echo.sh"},
+	}
+
+	err := param.Validate(s.repoDir)
+	s.Require().NoError(err)
+	s.Require().True(strings.HasSuffix(param.Value, "echo.sh test value"), param.Value)
+}
+
+func (s *HookTestSuite) TestValidationHookWithoutShebang() {
+	s.writeHook("upper.sh", "echo \"$2\" | tr '[:lower:]' '[:upper:]'\n", 0755)
+	param := &config.Param{
+		Name:           "test",
+		Value:          "value",
+		ValidationHook: &config.Hook{Path: "upper.sh"},
+	}
+
+	err := param.Validate(s.repoDir)
+	s.Require().NoError(err)
+	s.Require().Equal("VALUE", param.Value)
+}
+
+func (s *HookTestSuite) TestValidationHookWithInterpreter() {
+	s.writeHook("upper.sh", "echo \"$2\" | tr '[:lower:]' '[:upper:]'\n", 0644)
+	param := &config.Param{
+		Name:           "test",
+		Value:          "value",
+		ValidationHook: &config.Hook{Path: "upper.sh", Interpreter: "/bin/sh -e"},
+	}
+
+	err := param.Validate(s.repoDir)
+	s.Require().NoError(err)
+	s.Require().Equal("VALUE", param.Value)
+}
+
+func (s *HookTestSuite) TestExecuteHooksWithInterpreter() {
+	s.writeHook("exit.sh", "exit 0\n", 0644)
+	template := &config.Template{
+		PreInitHooks: []*config.Hook{
+			{Path: "exit.sh", Interpreter: "/bin/sh"},
+		},
+	}
+
+	err := template.Validate(s.repoDir)
+	s.Require().NoError(err)
+	err = template.ExecuteHooks(s.repoDir, config.PreInitHook)
+	s.Require().NoError(err)
+}
+
+func (s *HookTestSuite) TestValidateNonExecutableWithoutInterpreter() {
+	s.writeHook("hook.sh", "true\n", 0644)
+	template := &config.Template{
+		PreInitHooks: []*config.Hook{{Path: "hook.sh"}},
+	}
+
+	err := template.Validate(s.repoDir)
+	s.Require().EqualError(err, "hook hook.sh is not executable")
+}
+
+func (s *HookTestSuite) TestExecuteInlineHook() {
+	targetDir := s.T().TempDir()
+	s.T().Chdir(targetDir)
+	s.Require().NoError(os.Mkdir("api", 0755))
+	template := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:  "ship",
+				Value: "Serenity",
+			},
+		},
+		PostInitHooks: []*config.Hook{
+			{
+				Run:        `echo "$STENCILER_SHIP $CAPTAIN" > crew.txt`,
+				Env:        map[string]string{"CAPTAIN": "Malcolm"},
+				WorkingDir: "api",
+			},
+		},
+	}
+
+	err := template.Validate(s.repoDir)
+	s.Require().NoError(err)
+	err = template.ExecuteHooks(s.repoDir, config.PostInitHook)
+	s.Require().NoError(err)
+
+	b, err := os.ReadFile(filepath.Join(targetDir, "api", "crew.txt"))
+	s.Require().NoError(err)
+	s.Require().Equal("Serenity Malcolm\n", string(b))
+}
+
+func (s *HookTestSuite) TestExecuteInlineHookWithShell() {
+	template := &config.Template{
+		PreUpdateHooks: []*config.Hook{
+			{
+				Run:   "exit 3",
+				Shell: "/bin/sh -e",
+			},
+		},
+	}
+
+	err := template.ExecuteHooks(s.repoDir, config.PreUpdateHook)
+	s.Require().EqualError(err, "failed to execute hook exit 3: exit status 3")
+}
+
+func (s *HookTestSuite) TestInlineValidationHook() {
+	param := &config.Param{
+		Name:           "test",
+		Value:          "value",
+		ValidationHook: &config.Hook{Run: `echo "$1=$2"`},
+	}
+
+	err := param.Validate(s.repoDir)
+	s.Require().NoError(err)
+	s.Require().Equal("test=value", param.Value)
+}
+
+func (s *HookTestSuite) TestValidateInvalidHooks() {
+	template := &config.Template{
+		PreInitHooks: []*config.Hook{
+			{Path: "hook.sh", Run: "true"},
+			{Run: "true", Interpreter: "python3"},
+			{Env: map[string]string{"FOO": "bar"}},
+			{Path: "hook.sh", Shell: "bash"},
+			{Run: "true", WorkingDir: "../elsewhere"},
+			{Run: "false", WorkingDir: "/tmp"},
+			{Run: "true", Shell: "   "},
+			{Path: "hook.sh", Interpreter: " "},
+		},
+	}
+
+	err := template.Validate(s.repoDir)
+	s.Require().ErrorContains(err, "hook hook.sh sets both a path and a run command")
+	s.Require().ErrorContains(err, `hook "true" sets an interpreter for a run command, use shell instead`)
+	s.Require().ErrorContains(err, "hook sets neither a path nor a run command")
+	s.Require().ErrorContains(err, "hook hook.sh sets a shell for a script, use interpreter instead")
+	s.Require().ErrorContains(err, "hook true has a working-dir outside of the target directory")
+	s.Require().ErrorContains(err, "hook false has a working-dir outside of the target directory")
+	s.Require().ErrorContains(err, "hook true has a blank shell")
+	s.Require().ErrorContains(err, "hook hook.sh has a blank interpreter")
+}
+
+func (s *HookTestSuite) TestExecuteHooksWithBlankShell() {
+	template := &config.Template{
+		PreInitHooks: []*config.Hook{{Run: "true", Shell: "   "}},
+	}
+
+	err := template.ExecuteHooks(s.repoDir, config.PreInitHook)
+	s.Require().EqualError(err, "failed to execute hook true: invalid shell for hook true: command is blank")
+}
+
+func (s *HookTestSuite) TestExecuteHooksInDirectoryWithSpace() {
+	s.repoDir = filepath.Join(s.repoDir, "my repo")
+	err := os.Mkdir(s.repoDir, 0755)
+	s.Require().NoError(err)
+	s.writeHook("shebang hook.sh", "#!/bin/sh\necho shebang\n", 0755)
+	s.writeHook("plain hook.sh", "echo plain\n", 0755)
+	s.writeHook("interpreted hook.sh", "echo interpreted\n", 0644)
+	template := &config.Template{
+		PreInitHooks: []*config.Hook{
+			{Path: "shebang hook.sh"},
+			{Path: "plain hook.sh"},
+			{Path: "interpreted hook.sh", Interpreter: "/bin/sh -e"},
+		},
+	}
+
+	err = template.Validate(s.repoDir)
+	s.Require().NoError(err)
+	out := &strings.Builder{}
+	err = template.ExecuteHooksWithOut(s.repoDir, config.PreInitHook, out)
+	s.Require().NoError(err)
+	expected := "[shebang hook.sh] shebang\n[plain hook.sh] plain\n[/bin/sh -e interpreted hook.sh] interpreted\n"
+	s.Require().Equal(expected, out.String())
+}
+
+func (s *HookTestSuite) TestExecuteHooksStreamsOutput() {
+	s.writeHook("talk.sh", "#!/bin/sh\necho one\necho two >&2\nprintf three\n", 0755)
+	template := &config.Template{
+		PostUpdateHooks: []*config.Hook{
+			{Path: "talk.sh"},
+			{Run: "echo four; exit 1"},
+		},
+	}
+
+	out := &strings.Builder{}
+	err := template.ExecuteHooksWithOut(s.repoDir, config.PostUpdateHook, out)
+	s.Require().EqualError(err, "failed to execute hook echo four; exit 1: exit status 1")
+	expected := "[talk.sh] one\n[talk.sh] two\n[talk.sh] three\n[echo four; exit 1] four\n"
+	s.Require().Equal(expected, out.String())
+}
+
+func (s *HookTestSuite) TestExecuteHooksTimeout() {
+	template := &config.Template{
+		PreInitHooks: []*config.Hook{
+			{
+				Run:     "sleep 10",
+				Timeout: 100 * time.Millisecond,
+			},
+		},
+	}
+
+	start := time.Now()
+	err := template.ExecuteHooks(s.repoDir, config.PreInitHook)
+	s.Require().ErrorContains(err, "failed to execute hook sleep 10: timed out after 100ms")
+	s.Require().Less(time.Since(start), 5*time.Second)
+}
+
+func (s *HookTestSuite) TestValidateNegativeTimeout() {
+	template := &config.Template{
+		PreInitHooks: []*config.Hook{{Run: "true", Timeout: -time.Second}},
+	}
+
+	err := template.Validate(s.repoDir)
+	s.Require().EqualError(err, "hook true has a negative timeout")
+}
+
+func (s *HookTestSuite) TestSkipHooks() {
+	template := &config.Template{
+		SkipHooks: true,
+		PreInitHooks: []*config.Hook{
+			{Run: "exit 1"},
+		},
+	}
+
+	err := template.ExecuteHooks(s.repoDir, config.PreInitHook)
+	s.Require().NoError(err)
+
+	param := &config.Param{
+		Name:           "test",
+		Value:          "value",
+		MinLength:      6,
+		ValidationHook: &config.Hook{Run: "exit 1"},
+	}
+	err = template.ValidateParam(param, s.repoDir)
+	s.Require().EqualError(err, `value "value" for test must be at least 6 characters long`)
+
+	param.MinLength = 0
+	err = template.ValidateParam(param, s.repoDir)
+	s.Require().NoError(err)
+}
+
+func (s *HookTestSuite) TestFailurePolicies() {
+	template := &config.Template{
+		PostInitHooks: []*config.Hook{
+			{Run: "exit 1", FailurePolicy: config.IgnoreFailure},
+			{Run: "exit 2", FailurePolicy: config.WarnOnFailure},
+			{Run: "echo still running"},
+			{Run: "exit 3", FailurePolicy: config.FailOnFailure},
+			{Run: "echo never runs"},
+		},
+	}
+
+	out := &strings.Builder{}
+	err := template.ExecuteHooksWithOut(s.repoDir, config.PostInitHook, out)
+	s.Require().EqualError(err, "failed to execute hook exit 3: exit status 3")
+	expected := "warning: failed to execute hook exit 2: exit status 2\n[echo still running] still running\n"
+	s.Require().Equal(expected, out.String())
+}
+
+func (s *HookTestSuite) TestOnFailureHooks() {
+	template := &config.Template{
+		PostInitHooks:  []*config.Hook{{Run: "echo post-init"}},
+		OnFailureHooks: []*config.Hook{{Run: "echo cleaning up"}},
+	}
+
+	out := &strings.Builder{}
+	err := template.ExecuteHooksWithOut(s.repoDir, config.OnFailureHook, out)
+	s.Require().NoError(err)
+	s.Require().Equal("[echo cleaning up] cleaning up\n", out.String())
+	s.Require().Equal("on-failure", config.OnFailureHook.String())
+}
+
+func (s *HookTestSuite) TestValidateUnknownFailurePolicy() {
+	template := &config.Template{
+		OnFailureHooks: []*config.Hook{{Run: "true", FailurePolicy: "retry"}},
+	}
+
+	err := template.Validate(s.repoDir)
+	s.Require().EqualError(err, "hook true has an unknown failure-policy retry")
+}
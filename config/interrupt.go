@@ -0,0 +1,91 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+)
+
+// signalExitCodeBase is added to the number of the signal that stopped stenciler to give its exit code.
+const signalExitCodeBase = 128
+
+// InterruptedError is the error for work that was stopped, or never started, because stenciler received a signal.
+type InterruptedError struct {
+	// Signal is the signal stenciler received.
+	Signal os.Signal
+}
+
+// Error returns a description of the signal.
+func (e *InterruptedError) Error() string {
+	return "stopped by " + e.Signal.String()
+}
+
+// ExitCode returns the exit code of a process stopped by the signal.
+func (e *InterruptedError) ExitCode() int {
+	if s, ok := e.Signal.(syscall.Signal); ok {
+		return signalExitCodeBase + int(s)
+	}
+	return 1
+}
+
+var (
+	// interruptContext is cancelled with an *InterruptedError when stenciler receives a signal. All hooks except the
+	// on-failure hooks are run with it.
+	interruptContext, cancelInterruptContext = context.WithCancelCause(context.Background())
+
+	hookCmdsLock sync.Mutex
+	// hookCmds holds the commands of the running hooks so that they can be killed.
+	hookCmds = make(map[*exec.Cmd]struct{})
+)
+
+// Interrupt stops the hooks because stenciler received the signal. Running hooks are forwarded the signal and are
+// killed if they have not exited after a short delay. Hooks that would start afterwards fail with an *InterruptedError
+// instead, as does Interrupted. The on-failure hooks are not affected so that they can still clean up.
+func Interrupt(sig os.Signal) {
+	slog.Debug("interrupting hooks", slog.String("signal", sig.String()))
+	cancelInterruptContext(&InterruptedError{Signal: sig})
+}
+
+// Interrupted returns an *InterruptedError if Interrupt has been called, otherwise nil.
+func Interrupted() error {
+	if interruptContext.Err() == nil {
+		return nil
+	}
+	return context.Cause(interruptContext)
+}
+
+// KillHooks kills every running hook, including the on-failure hooks, along with any processes they started.
+func KillHooks() {
+	hookCmdsLock.Lock()
+	defer hookCmdsLock.Unlock()
+	for cmd := range hookCmds {
+		if err := signalProcessGroup(cmd, syscall.SIGKILL); err != nil {
+			slog.Debug("unable to kill hook", slog.String("hook", cmd.String()), slog.Any("error", err))
+		}
+	}
+}
+
+func trackHookCmd(cmd *exec.Cmd) {
+	hookCmdsLock.Lock()
+	defer hookCmdsLock.Unlock()
+	hookCmds[cmd] = struct{}{}
+}
+
+func untrackHookCmd(cmd *exec.Cmd) {
+	hookCmdsLock.Lock()
+	defer hookCmdsLock.Unlock()
+	delete(hookCmds, cmd)
+}
+
+// interruptSignal returns the signal stenciler received if ctx was cancelled by Interrupt.
+func interruptSignal(ctx context.Context) (os.Signal, bool) {
+	var interrupted *InterruptedError
+	if errors.As(context.Cause(ctx), &interrupted) {
+		return interrupted.Signal, true
+	}
+	return nil, false
+}
@@ -1,52 +1,158 @@
 package config
 
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
 // Merge merges the local template with the repository template. It uses the contents of the repository template
-// and fills in the values of parameters from the local template. It also sets the repository URL to the value from
-// the local template.
-func Merge(repoTemplate, localTemplate *Template) *Template {
+// and fills in the values of parameters from the local template. Values recorded under a previous name of a parameter
+// are carried forward and any migrations newer than the recorded revision are applied. A recorded value that was an
+// accepted default is dropped if the default has since changed so that the new default is offered. It also sets the
+// repository URL to the value from the local template. Unmanaged parameters from the local template are kept unless
+// the repository template defines them again.
+func Merge(repoTemplate, localTemplate *Template) (*Template, error) {
+	localParams := slices.Concat(localTemplate.Unmanaged, localTemplate.Params)
+	params, err := mergeParams(repoTemplate.Params, localParams)
+	if err != nil {
+		return nil, err
+	}
+
 	merged := Template{}
 	merged.Repository = localTemplate.Repository
 	merged.Directory = repoTemplate.Directory
-	merged.Params = mergeParams(repoTemplate.Params, localTemplate.Params)
+	merged.Params = params
+	merged.Unmanaged = unclaimedParams(repoTemplate.Params, localTemplate.Unmanaged)
 	merged.InitOnlyPaths = repoTemplate.InitOnlyPaths
 	merged.RawCopyPaths = repoTemplate.RawCopyPaths
-	merged.PreInitHookPaths = repoTemplate.PreInitHookPaths
-	merged.PostInitHookPaths = repoTemplate.PostInitHookPaths
-	merged.PreUpdateHookPaths = repoTemplate.PreUpdateHookPaths
-	merged.PostUpdateHookPaths = repoTemplate.PostUpdateHookPaths
+	merged.GoFormat = repoTemplate.GoFormat
+	merged.Processors = repoTemplate.Processors
+	merged.HookEnv = repoTemplate.HookEnv
+	merged.PreInitHooks = repoTemplate.PreInitHooks
+	merged.PostInitHooks = repoTemplate.PostInitHooks
+	merged.PreUpdateHooks = repoTemplate.PreUpdateHooks
+	merged.PostUpdateHooks = repoTemplate.PostUpdateHooks
+	merged.OnFailureHooks = repoTemplate.OnFailureHooks
 
-	return &merged
+	return &merged, nil
 }
 
-func mergeParams(repoParams, localParams []*Param) []*Param {
+// Orphans returns the prompted parameters recorded in the local template that are no longer defined by the repository
+// template, either by name or by a previous name. Internal parameters are not included, as their values come from the
+// template and are never carried forward.
+func Orphans(repoTemplate, localTemplate *Template) []*Param {
+	var orphans []*Param
+	for _, p := range unclaimedParams(repoTemplate.Params, localTemplate.Params) {
+		if len(p.Prompt) > 0 {
+			orphans = append(orphans, p)
+		}
+	}
+	return orphans
+}
+
+// unclaimedParams returns the local params that are not matched by the name or a previous name of any repo param.
+func unclaimedParams(repoParams, localParams []*Param) []*Param {
+	names := make(map[string]bool, len(repoParams))
+	for _, p := range repoParams {
+		names[p.Name] = true
+		for _, name := range p.RenamedFrom {
+			names[name] = true
+		}
+	}
+
+	var unclaimed []*Param
+	for _, p := range localParams {
+		if !names[p.Name] {
+			unclaimed = append(unclaimed, p)
+		}
+	}
+	return unclaimed
+}
+
+func mergeParams(repoParams, localParams []*Param) ([]*Param, error) {
 	params := make([]*Param, 0, len(repoParams))
 	localValues := localParamValues(localParams)
 	for _, p := range repoParams {
-		param := &Param{
-			Name:           p.Name,
-			Prompt:         p.Prompt,
-			Default:        p.Default,
-			ValidationHook: p.ValidationHook,
-			Value:          p.Value,
-		}
-		if value, ok := localValues[p.Name]; ok {
+		param := new(Param)
+		*param = *p
+		if local := findLocalParam(p, localValues); local != nil && !defaultChanged(p, local) {
+			value, err := migrateValue(p, local, localValues)
+			if err != nil {
+				return nil, err
+			}
 			param.Value = value
+			param.Source = local.Source
 		}
 		params = append(params, param)
 	}
 	if len(params) == 0 {
-		return nil
+		return nil, nil
 	}
-	return params
+	return params, nil
 }
 
-func localParamValues(params []*Param) map[string]string {
-	values := make(map[string]string, len(params))
+func localParamValues(params []*Param) map[string]*Param {
+	values := make(map[string]*Param, len(params))
 	for _, p := range params {
 		if len(p.Prompt) > 0 {
 			// only add if it was prompted for
-			values[p.Name] = p.Value
+			values[p.Name] = p
 		}
 	}
 	return values
 }
+
+// findLocalParam returns the local param recorded under the name of the repo param or, failing that, under the first
+// of its previous names that has a recorded param.
+func findLocalParam(repoParam *Param, localValues map[string]*Param) *Param {
+	if local, ok := localValues[repoParam.Name]; ok {
+		return local
+	}
+	for _, name := range repoParam.RenamedFrom {
+		if local, ok := localValues[name]; ok {
+			return local
+		}
+	}
+	return nil
+}
+
+// defaultChanged returns true if the local value is a default the user accepted and the repo param now offers a
+// different default, in which case the new default should be offered instead of carrying the value forward.
+func defaultChanged(repoParam, localParam *Param) bool {
+	if localParam.Source != DefaultValue {
+		return false
+	}
+	return repoParam.Default != localParam.Default || !slices.Equal(repoParam.DefaultFrom, localParam.DefaultFrom)
+}
+
+// migrateValue applies, in revision order, the migrations of the repo param that are newer than the revision of the
+// local param and returns the resulting value.
+func migrateValue(repoParam, localParam *Param, localValues map[string]*Param) (string, error) {
+	value := localParam.Value
+	if len(value) == 0 {
+		return value, nil
+	}
+
+	migrations := slices.Clone(repoParam.Migrations)
+	slices.SortStableFunc(migrations, func(a, b *Migration) int {
+		return cmp.Compare(a.Revision, b.Revision)
+	})
+
+	values := make(map[string]string, len(localValues))
+	for name, p := range localValues {
+		values[name] = p.Value
+	}
+
+	for _, m := range migrations {
+		if m.Revision <= localParam.Revision {
+			continue
+		}
+		var err error
+		value, err = m.apply(value, values)
+		if err != nil {
+			return "", fmt.Errorf("failed to migrate %s to revision %d: %w", repoParam.Name, m.Revision, err)
+		}
+	}
+	return value, nil
+}
@@ -18,13 +18,16 @@ func TestMergeTestSuite(t *testing.T) {
 
 func (s *MergeTestSuite) TestMergeRepoTemplateWithNoParams() {
 	repo := &config.Template{
-		Directory:           "foo",
-		InitOnlyPaths:       []string{"init1"},
-		RawCopyPaths:        []string{"raw1"},
-		PreInitHookPaths:    []string{"pre-init1"},
-		PostInitHookPaths:   []string{"post-init1"},
-		PreUpdateHookPaths:  []string{"pre-update1"},
-		PostUpdateHookPaths: []string{"post-update1"},
+		Directory:       "foo",
+		InitOnlyPaths:   []string{"init1"},
+		RawCopyPaths:    []string{"raw1"},
+		GoFormat:        true,
+		HookEnv:         &config.HookEnv{Restricted: true, Pass: []string{"GOPROXY"}},
+		PreInitHooks:    []*config.Hook{{Path: "pre-init1"}},
+		PostInitHooks:   []*config.Hook{{Path: "post-init1"}},
+		PreUpdateHooks:  []*config.Hook{{Path: "pre-update1"}},
+		PostUpdateHooks: []*config.Hook{{Path: "post-update1"}},
+		OnFailureHooks:  []*config.Hook{{Path: "on-failure1"}},
 	}
 	local := &config.Template{
 		Repository: "https://github.com/owner/repo.git",
@@ -35,24 +38,29 @@ func (s *MergeTestSuite) TestMergeRepoTemplateWithNoParams() {
 				Value: "value1",
 			},
 		},
-		InitOnlyPaths:       []string{"init2"},
-		RawCopyPaths:        []string{"raw2"},
-		PreInitHookPaths:    []string{"pre-init2"},
-		PostInitHookPaths:   []string{"post-init2"},
-		PreUpdateHookPaths:  []string{"pre-update2"},
-		PostUpdateHookPaths: []string{"post-update2"},
+		InitOnlyPaths:   []string{"init2"},
+		RawCopyPaths:    []string{"raw2"},
+		HookEnv:         &config.HookEnv{Pass: []string{"AWS_SECRET_ACCESS_KEY"}},
+		PreInitHooks:    []*config.Hook{{Path: "pre-init2"}},
+		PostInitHooks:   []*config.Hook{{Path: "post-init2"}},
+		PreUpdateHooks:  []*config.Hook{{Path: "pre-update2"}},
+		PostUpdateHooks: []*config.Hook{{Path: "post-update2"}},
 	}
 	expected := &config.Template{
-		Repository:          "https://github.com/owner/repo.git",
-		Directory:           "foo",
-		InitOnlyPaths:       []string{"init1"},
-		RawCopyPaths:        []string{"raw1"},
-		PreInitHookPaths:    []string{"pre-init1"},
-		PostInitHookPaths:   []string{"post-init1"},
-		PreUpdateHookPaths:  []string{"pre-update1"},
-		PostUpdateHookPaths: []string{"post-update1"},
-	}
-	actual := config.Merge(repo, local)
+		Repository:      "https://github.com/owner/repo.git",
+		Directory:       "foo",
+		InitOnlyPaths:   []string{"init1"},
+		RawCopyPaths:    []string{"raw1"},
+		GoFormat:        true,
+		HookEnv:         &config.HookEnv{Restricted: true, Pass: []string{"GOPROXY"}},
+		PreInitHooks:    []*config.Hook{{Path: "pre-init1"}},
+		PostInitHooks:   []*config.Hook{{Path: "post-init1"}},
+		PreUpdateHooks:  []*config.Hook{{Path: "pre-update1"}},
+		PostUpdateHooks: []*config.Hook{{Path: "post-update1"}},
+		OnFailureHooks:  []*config.Hook{{Path: "on-failure1"}},
+	}
+	actual, err := config.Merge(repo, local)
+	s.Require().NoError(err)
 	s.Require().Equal(expected, actual)
 }
 
@@ -76,12 +84,12 @@ func (s *MergeTestSuite) TestMergeRepoTemplateWithOnlyNewParams() {
 				Value: "value1",
 			},
 		},
-		InitOnlyPaths:       []string{"init2"},
-		RawCopyPaths:        []string{"raw2"},
-		PreInitHookPaths:    []string{"pre-init2"},
-		PostInitHookPaths:   []string{"post-init2"},
-		PreUpdateHookPaths:  []string{"pre-update2"},
-		PostUpdateHookPaths: []string{"post-update2"},
+		InitOnlyPaths:   []string{"init2"},
+		RawCopyPaths:    []string{"raw2"},
+		PreInitHooks:    []*config.Hook{{Path: "pre-init2"}},
+		PostInitHooks:   []*config.Hook{{Path: "post-init2"}},
+		PreUpdateHooks:  []*config.Hook{{Path: "pre-update2"}},
+		PostUpdateHooks: []*config.Hook{{Path: "post-update2"}},
 	}
 	expected := &config.Template{
 		Repository: "https://github.com/owner/repo.git",
@@ -94,7 +102,8 @@ func (s *MergeTestSuite) TestMergeRepoTemplateWithOnlyNewParams() {
 			},
 		},
 	}
-	actual := config.Merge(repo, local)
+	actual, err := config.Merge(repo, local)
+	s.Require().NoError(err)
 	s.Require().Equal(expected, actual)
 }
 
@@ -106,7 +115,7 @@ func (s *MergeTestSuite) TestMergeTemplatesHaveMatchingParams() {
 				Name:           "param1",
 				Prompt:         "prompt2",
 				Default:        "mine",
-				ValidationHook: "hook1",
+				ValidationHook: &config.Hook{Path: "hook1"},
 			},
 		},
 	}
@@ -130,12 +139,13 @@ func (s *MergeTestSuite) TestMergeTemplatesHaveMatchingParams() {
 				Name:           "param1",
 				Prompt:         "prompt2",
 				Default:        "mine",
-				ValidationHook: "hook1",
+				ValidationHook: &config.Hook{Path: "hook1"},
 				Value:          "value1",
 			},
 		},
 	}
-	actual := config.Merge(repo, local)
+	actual, err := config.Merge(repo, local)
+	s.Require().NoError(err)
 	s.Require().Equal(expected, actual)
 }
 
@@ -147,7 +157,7 @@ func (s *MergeTestSuite) TestMergeTemplatesHaveMatchingParamsLocalNoPrompt() {
 				Name:           "param1",
 				Prompt:         "prompt2",
 				Default:        "mine",
-				ValidationHook: "hook1",
+				ValidationHook: &config.Hook{Path: "hook1"},
 			},
 		},
 	}
@@ -169,10 +179,355 @@ func (s *MergeTestSuite) TestMergeTemplatesHaveMatchingParamsLocalNoPrompt() {
 				Name:           "param1",
 				Prompt:         "prompt2",
 				Default:        "mine",
-				ValidationHook: "hook1",
+				ValidationHook: &config.Hook{Path: "hook1"},
+			},
+		},
+	}
+	actual, err := config.Merge(repo, local)
+	s.Require().NoError(err)
+	s.Require().Equal(expected, actual)
+}
+
+func (s *MergeTestSuite) TestMergeRenamedParam() {
+	repo := &config.Template{
+		Directory: "foo",
+		Params: []*config.Param{
+			{
+				Name:        "service_name",
+				Prompt:      "prompt1",
+				RenamedFrom: []string{"name", "svc_name"},
+			},
+		},
+	}
+	local := &config.Template{
+		Repository: "https://github.com/owner/repo.git",
+		Directory:  "foo",
+		Params: []*config.Param{
+			{
+				Name:   "svc_name",
+				Prompt: "prompt1",
+				Value:  "value1",
+			},
+		},
+	}
+	expected := &config.Template{
+		Repository: "https://github.com/owner/repo.git",
+		Directory:  "foo",
+		Params: []*config.Param{
+			{
+				Name:        "service_name",
+				Prompt:      "prompt1",
+				RenamedFrom: []string{"name", "svc_name"},
+				Value:       "value1",
+			},
+		},
+	}
+	actual, err := config.Merge(repo, local)
+	s.Require().NoError(err)
+	s.Require().Equal(expected, actual)
+}
+
+func (s *MergeTestSuite) TestMergeRenamedParamPrefersCurrentName() {
+	repo := &config.Template{
+		Directory: "foo",
+		Params: []*config.Param{
+			{
+				Name:        "service_name",
+				Prompt:      "prompt1",
+				RenamedFrom: []string{"svc_name"},
+			},
+		},
+	}
+	local := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:   "svc_name",
+				Prompt: "prompt1",
+				Value:  "old",
+			},
+			{
+				Name:   "service_name",
+				Prompt: "prompt1",
+				Value:  "new",
+			},
+		},
+	}
+	actual, err := config.Merge(repo, local)
+	s.Require().NoError(err)
+	s.Require().Equal("new", actual.Params[0].Value)
+}
+
+func (s *MergeTestSuite) TestMergeAppliesMigrations() {
+	repo := &config.Template{
+		Directory: "foo",
+		Params: []*config.Param{
+			{
+				Name:        "service_name",
+				Prompt:      "prompt1",
+				RenamedFrom: []string{"svc_name"},
+				Revision:    3,
+				Migrations: []*config.Migration{
+					{
+						Revision:  3,
+						Transform: `{{ .Params.org }}-{{ .Value }}`,
+					},
+					{
+						Revision:  1,
+						Transform: `{{ .Value | upper }}`,
+					},
+					{
+						Revision:  2,
+						Transform: `{{ .Value | kebab }}`,
+					},
+				},
+			},
+		},
+	}
+	local := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:   "org",
+				Prompt: "prompt1",
+				Value:  "acme",
+			},
+			{
+				Name:     "svc_name",
+				Prompt:   "prompt1",
+				Revision: 1,
+				Value:    "MyService",
+			},
+		},
+	}
+	actual, err := config.Merge(repo, local)
+	s.Require().NoError(err)
+	s.Require().Equal("acme-my-service", actual.Params[0].Value)
+	s.Require().Equal(3, actual.Params[0].Revision)
+}
+
+func (s *MergeTestSuite) TestMergeSkipsAppliedMigrations() {
+	repo := &config.Template{
+		Directory: "foo",
+		Params: []*config.Param{
+			{
+				Name:     "param1",
+				Prompt:   "prompt1",
+				Revision: 1,
+				Migrations: []*config.Migration{
+					{
+						Revision:  1,
+						Transform: `{{ .Value | upper }}`,
+					},
+				},
+			},
+		},
+	}
+	local := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:     "param1",
+				Prompt:   "prompt1",
+				Revision: 1,
+				Value:    "value1",
+			},
+		},
+	}
+	actual, err := config.Merge(repo, local)
+	s.Require().NoError(err)
+	s.Require().Equal("value1", actual.Params[0].Value)
+}
+
+func (s *MergeTestSuite) TestMergeFailedMigration() {
+	repo := &config.Template{
+		Directory: "foo",
+		Params: []*config.Param{
+			{
+				Name:     "param1",
+				Prompt:   "prompt1",
+				Revision: 1,
+				Migrations: []*config.Migration{
+					{
+						Revision:  1,
+						Transform: `{{ .Params.missing }}`,
+					},
+				},
+			},
+		},
+	}
+	local := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:   "param1",
+				Prompt: "prompt1",
+				Value:  "value1",
+			},
+		},
+	}
+	_, err := config.Merge(repo, local)
+	s.Require().ErrorContains(err, "failed to migrate param1 to revision 1")
+}
+
+func (s *MergeTestSuite) TestOrphans() {
+	repo := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:        "param1",
+				Prompt:      "prompt1",
+				RenamedFrom: []string{"old1"},
+			},
+		},
+	}
+	local := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:   "old1",
+				Prompt: "prompt1",
+				Value:  "value1",
+			},
+			{
+				Name:   "param2",
+				Prompt: "prompt2",
+				Value:  "value2",
+			},
+			{
+				Name:  "param3",
+				Value: "value3",
+			},
+		},
+	}
+	expected := []*config.Param{
+		{
+			Name:   "param2",
+			Prompt: "prompt2",
+			Value:  "value2",
+		},
+	}
+	s.Require().Equal(expected, config.Orphans(repo, local))
+}
+
+func (s *MergeTestSuite) TestMergeKeepsUnmanagedParams() {
+	repo := &config.Template{
+		Directory: "foo",
+		Params: []*config.Param{
+			{
+				Name:   "param1",
+				Prompt: "prompt1",
+			},
+		},
+	}
+	local := &config.Template{
+		Repository: "https://github.com/owner/repo.git",
+		Directory:  "foo",
+		Unmanaged: []*config.Param{
+			{
+				Name:   "param1",
+				Prompt: "prompt1",
+				Value:  "value1",
+			},
+			{
+				Name:   "param2",
+				Prompt: "prompt2",
+				Value:  "value2",
+			},
+		},
+	}
+	expected := &config.Template{
+		Repository: "https://github.com/owner/repo.git",
+		Directory:  "foo",
+		Params: []*config.Param{
+			{
+				Name:   "param1",
+				Prompt: "prompt1",
+				Value:  "value1",
+			},
+		},
+		Unmanaged: []*config.Param{
+			{
+				Name:   "param2",
+				Prompt: "prompt2",
+				Value:  "value2",
 			},
 		},
 	}
-	actual := config.Merge(repo, local)
+	actual, err := config.Merge(repo, local)
+	s.Require().NoError(err)
 	s.Require().Equal(expected, actual)
 }
+
+func (s *MergeTestSuite) TestMergeCarriesSource() {
+	repo := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:    "param1",
+				Prompt:  "prompt1",
+				Default: "mine",
+			},
+		},
+	}
+	local := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:    "param1",
+				Prompt:  "prompt1",
+				Default: "mine",
+				Value:   "mine",
+				Source:  config.DefaultValue,
+			},
+		},
+	}
+	actual, err := config.Merge(repo, local)
+	s.Require().NoError(err)
+	s.Require().Equal("mine", actual.Params[0].Value)
+	s.Require().Equal(config.DefaultValue, actual.Params[0].Source)
+}
+
+func (s *MergeTestSuite) TestMergeOffersChangedDefault() {
+	repo := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:    "param1",
+				Prompt:  "prompt1",
+				Default: "new",
+			},
+			{
+				Name:    "param2",
+				Prompt:  "prompt2",
+				Default: "new",
+			},
+		},
+	}
+	local := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:    "param1",
+				Prompt:  "prompt1",
+				Default: "old",
+				Value:   "old",
+				Source:  config.DefaultValue,
+			},
+			{
+				Name:    "param2",
+				Prompt:  "prompt2",
+				Default: "old",
+				Value:   "old",
+				Source:  config.PromptedValue,
+			},
+		},
+	}
+	expected := []*config.Param{
+		{
+			Name:    "param1",
+			Prompt:  "prompt1",
+			Default: "new",
+		},
+		{
+			Name:    "param2",
+			Prompt:  "prompt2",
+			Default: "new",
+			Value:   "old",
+			Source:  config.PromptedValue,
+		},
+	}
+	actual, err := config.Merge(repo, local)
+	s.Require().NoError(err)
+	s.Require().Equal(expected, actual.Params)
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/iancoleman/strcase"
+)
+
+// migrationFuncs are the functions available to migration transforms in addition to the text/template builtins.
+var migrationFuncs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"trim":       strings.TrimSpace,
+	"trimPrefix": strings.TrimPrefix,
+	"trimSuffix": strings.TrimSuffix,
+	"replace":    strings.ReplaceAll,
+	"snake":      strcase.ToSnake,
+	"kebab":      strcase.ToKebab,
+	"camel":      strcase.ToCamel,
+	"lowerCamel": strcase.ToLowerCamel,
+}
+
+// migrationData is the data a migration transform is executed with.
+type migrationData struct {
+	Value  string
+	Params map[string]string
+}
+
+func (m *Migration) parse() (*template.Template, error) {
+	return template.New("transform").Funcs(migrationFuncs).Option("missingkey=error").Parse(m.Transform)
+}
+
+// apply runs the transform on the value and returns the result with surrounding whitespace removed.
+func (m *Migration) apply(value string, params map[string]string) (string, error) {
+	tmpl, err := m.parse()
+	if err != nil {
+		return "", err
+	}
+	out := &strings.Builder{}
+	err = tmpl.Execute(out, migrationData{Value: value, Params: params})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// validateMigrations ensures that the migrations for the parameter are well formed.
+func (p *Param) validateMigrations() error {
+	var errs []error
+	for _, m := range p.Migrations {
+		if m.Revision <= 0 || m.Revision > p.Revision {
+			errs = append(errs, fmt.Errorf("param %s has a migration to revision %d outside of 1 to %d",
+				p.Name, m.Revision, p.Revision))
+		}
+		if _, err := m.parse(); err != nil {
+			errs = append(errs, fmt.Errorf("param %s has an invalid migration transform: %w", p.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
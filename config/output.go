@@ -0,0 +1,56 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// hookOutputWriter writes each line of output from a hook to out prefixed with the name of the hook. Each line is also
+// logged at debug level. A trailing partial line is held until the writer is flushed.
+type hookOutputWriter struct {
+	hook string
+	out  io.Writer
+	buf  []byte
+}
+
+func newHookOutputWriter(hook *Hook, out io.Writer) *hookOutputWriter {
+	return &hookOutputWriter{
+		hook: hook.String(),
+		out:  out,
+	}
+}
+
+// Write writes each complete line in p.
+func (w *hookOutputWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(w.buf[:i])
+		w.buf = w.buf[i+1:]
+		if err := w.writeLine(line); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes any trailing partial line.
+func (w *hookOutputWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := string(w.buf)
+	w.buf = nil
+	return w.writeLine(line)
+}
+
+func (w *hookOutputWriter) writeLine(line string) error {
+	slog.Debug("hook output", slog.String("hook", w.hook), slog.String("line", line))
+	_, err := fmt.Fprintf(w.out, "[%s] %s\n", w.hook, line)
+	return err
+}
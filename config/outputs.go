@@ -0,0 +1,120 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// OutputFileEnv is the environment variable that holds the path of the file pre hooks write their outputs to.
+const OutputFileEnv = BuiltInsEnvPrefix + "OUTPUT"
+
+// hookOutput is a value published by a hook.
+type hookOutput struct {
+	name  string
+	value string
+}
+
+// publishesOutputs returns true if hooks of the class may publish outputs. Only the hooks that run before the template
+// files are rendered can affect them.
+func publishesOutputs(hookClass HookClass) bool {
+	return hookClass == PreInitHook || hookClass == PreUpdateHook
+}
+
+// readHookOutputs reads the outputs the hook wrote to the output file and applies them to the template. An output
+// named after an internal param sets the value of the param, which is validated in the same way as a value entered at
+// the prompt and is recorded in the config file unless the param is secret. An output named after a prompted param is
+// an error, as the user has already confirmed its value. Any other output is kept in HookOutputs and is not recorded.
+func (t *Template) readHookOutputs(repoDir string, hook *Hook, outputFile string) error {
+	f, err := os.Open(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to read outputs of hook %s: %w", hook, err)
+	}
+	defer f.Close()
+
+	outputs, err := parseHookOutputs(f)
+	if err != nil {
+		return fmt.Errorf("failed to read outputs of hook %s: %w", hook, err)
+	}
+	for _, o := range outputs {
+		if err := t.applyHookOutput(repoDir, o); err != nil {
+			return fmt.Errorf("invalid output from hook %s: %w", hook, err)
+		}
+	}
+	return nil
+}
+
+func (t *Template) applyHookOutput(repoDir string, o hookOutput) error {
+	for _, p := range t.Params {
+		if p.Name != o.name {
+			continue
+		}
+		if len(p.Prompt) > 0 {
+			return fmt.Errorf("param %s is prompted and cannot be set by a hook", p.Name)
+		}
+		p.Value = o.value
+		p.Source = HookValue
+		if err := t.ValidateParam(p, repoDir); err != nil {
+			return err
+		}
+		slog.Debug("param set by hook output", slog.Any("param", *p))
+		return nil
+	}
+
+	if err := (&Param{Name: o.name}).validateName(); err != nil {
+		return err
+	}
+	if t.HookOutputs == nil {
+		t.HookOutputs = make(map[string]string)
+	}
+	t.HookOutputs[o.name] = o.value
+	slog.Debug("hook output", slog.String("name", o.name))
+	return nil
+}
+
+// parseHookOutputs parses outputs in the form used by GitHub Actions. Each output is either a single name=value line
+// or a name<<delimiter line followed by the lines of a multiline value and a line holding only the delimiter. Empty
+// lines are ignored.
+func parseHookOutputs(r io.Reader) ([]hookOutput, error) {
+	var outputs []hookOutput
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(strings.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		if name, delimiter, ok := strings.Cut(line, "<<"); ok && !strings.Contains(name, "=") {
+			if len(name) == 0 || len(delimiter) == 0 {
+				return nil, fmt.Errorf("output %q is not in the form name<<delimiter", line)
+			}
+			var lines []string
+			closed := false
+			for scanner.Scan() {
+				if scanner.Text() == delimiter {
+					closed = true
+					break
+				}
+				lines = append(lines, scanner.Text())
+			}
+			if !closed {
+				return nil, fmt.Errorf("missing delimiter %s for output %s", delimiter, name)
+			}
+			outputs = append(outputs, hookOutput{name: name, value: strings.Join(lines, "\n")})
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		if !ok || len(name) == 0 {
+			return nil, fmt.Errorf("output %q is not in the form name=value", line)
+		}
+		outputs = append(outputs, hookOutput{name: name, value: value})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return outputs, nil
+}
@@ -0,0 +1,138 @@
+package config_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rogueserenity/stenciler/config"
+)
+
+type HookOutputsTestSuite struct {
+	suite.Suite
+
+	repoDir  string
+	template *config.Template
+}
+
+func TestHookOutputsTestSuite(t *testing.T) {
+	suite.Run(t, new(HookOutputsTestSuite))
+}
+
+func (s *HookOutputsTestSuite) SetupTest() {
+	s.repoDir = s.T().TempDir()
+	s.template = &config.Template{
+		Repository: "https://github.com/rogueserenity/stenciler",
+		Directory:  "root",
+		Params: []*config.Param{
+			{
+				Name:    "ship_id",
+				Pattern: "[a-z0-9]+",
+			},
+			{
+				Name:   "pilot",
+				Prompt: "Pilot",
+				Value:  "Wash",
+			},
+			{
+				Name: "captain",
+				ValidationHook: &config.Hook{
+					Run: `[ "$2" != Jayne ] || { echo "$2 is not a captain" >&2; exit 65; }; echo "$2"`,
+				},
+			},
+		},
+	}
+}
+
+func (s *HookOutputsTestSuite) TestOutputs() {
+	outputs := `ship_id=abc123\ncaptain=Mal\nport=8080\n\nnotes<<EOF\nshiny\nEOF\n`
+	s.template.PreInitHooks = []*config.Hook{
+		{Run: `printf '` + outputs + `' >> "$` + config.OutputFileEnv + `"`},
+		{Run: `echo "$STENCILER_SHIP_ID $STENCILER_PORT"`},
+	}
+
+	out := &strings.Builder{}
+	err := s.template.ExecuteHooksWithOut(s.repoDir, config.PreInitHook, out)
+	s.Require().NoError(err)
+	s.Require().Equal(`[echo "$STENCILER_SHIP_ID $STENCILER_PORT"] abc123 8080`+"\n", out.String())
+
+	param := s.template.Params[0]
+	s.Require().Equal("abc123", param.Value)
+	s.Require().Equal(config.HookValue, param.Source)
+	s.Require().Equal("Mal", s.template.Params[2].Value)
+	s.Require().Equal(map[string]string{"port": "8080", "notes": "shiny"}, s.template.HookOutputs)
+
+	cfgText := &strings.Builder{}
+	err = (&config.Config{Templates: []*config.Template{s.template}}).Write(cfgText)
+	s.Require().NoError(err)
+	s.Require().Contains(cfgText.String(), "value: abc123")
+	s.Require().NotContains(cfgText.String(), "port:")
+}
+
+func (s *HookOutputsTestSuite) TestOutputsOnlyForPreHooks() {
+	s.template.PostInitHooks = []*config.Hook{
+		{Run: `echo "${` + config.OutputFileEnv + `:-unset}"`},
+	}
+
+	out := &strings.Builder{}
+	err := s.template.ExecuteHooksWithOut(s.repoDir, config.PostInitHook, out)
+	s.Require().NoError(err)
+	s.Require().True(strings.HasSuffix(out.String(), "] unset\n"), out.String())
+}
+
+func (s *HookOutputsTestSuite) TestInvalidOutputs() {
+	tests := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{
+			name:     "not name=value",
+			output:   `port`,
+			expected: `output "port" is not in the form name=value`,
+		},
+		{
+			name:     "missing name",
+			output:   `=8080`,
+			expected: `output "=8080" is not in the form name=value`,
+		},
+		{
+			name:     "missing delimiter",
+			output:   `notes<<EOF\nshiny`,
+			expected: "missing delimiter EOF for output notes",
+		},
+		{
+			name:     "reserved name",
+			output:   `Stenciler=shiny`,
+			expected: "param name Stenciler is reserved",
+		},
+		{
+			name:     "param rules",
+			output:   `ship_id=ABC`,
+			expected: `value "ABC" for ship_id must match the pattern [a-z0-9]+`,
+		},
+		{
+			name:     "prompted param",
+			output:   `pilot=Zoe`,
+			expected: "param pilot is prompted and cannot be set by a hook",
+		},
+		{
+			name:     "validation hook",
+			output:   `captain=Jayne`,
+			expected: "Jayne is not a captain",
+		},
+	}
+
+	for _, test := range tests {
+		s.Run(test.name, func() {
+			s.SetupTest()
+			s.template.PreUpdateHooks = []*config.Hook{
+				{Run: `printf '` + test.output + `\n' > "$` + config.OutputFileEnv + `"`},
+			}
+
+			err := s.template.ExecuteHooksWithOut(s.repoDir, config.PreUpdateHook, &strings.Builder{})
+			s.Require().ErrorContains(err, test.expected)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package config
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup does nothing as process groups are only supported on unix.
+func setProcessGroup(_ *exec.Cmd) {}
+
+// signalProcessGroup kills the started command as signals other than kill cannot be sent to processes on this platform.
+func signalProcessGroup(cmd *exec.Cmd, _ os.Signal) error {
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,130 @@
+//go:build unix
+
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rogueserenity/stenciler/config"
+)
+
+// interruptWhenStarted calls config.Interrupt with the signal once the marker file exists, followed by KillHooks if
+// kill is true.
+func (s *HookTestSuite) interruptWhenStarted(marker string, sig os.Signal, kill bool) {
+	s.T().Cleanup(config.ResetInterrupt)
+	go func() {
+		s.Eventually(func() bool {
+			_, err := os.Stat(marker)
+			return err == nil
+		}, 5*time.Second, 10*time.Millisecond)
+		config.Interrupt(sig)
+		if kill {
+			config.KillHooks()
+		}
+	}()
+}
+
+func (s *HookTestSuite) TestExecuteHooksForwardsSignals() {
+	started := filepath.Join(s.repoDir, "started")
+	template := &config.Template{
+		PostInitHooks: []*config.Hook{
+			{
+				Run: `trap 'echo interrupted; exit 7' INT; touch "$MARKER"; while true; do sleep 0.1; done`,
+				Env: map[string]string{"MARKER": started},
+			},
+		},
+	}
+	s.interruptWhenStarted(started, syscall.SIGINT, false)
+
+	out := &strings.Builder{}
+	err := template.ExecuteHooksWithOut(s.repoDir, config.PostInitHook, out)
+	var interrupted *config.InterruptedError
+	s.Require().ErrorAs(err, &interrupted)
+	s.Require().Equal(syscall.SIGINT, interrupted.Signal)
+	s.Require().Equal(130, interrupted.ExitCode())
+	s.Require().ErrorContains(err, "stopped by interrupt: exit status 7")
+	s.Require().Contains(out.String(), "interrupted")
+}
+
+func (s *HookTestSuite) TestInterruptedHookThatExitsCleanly() {
+	started := filepath.Join(s.repoDir, "started")
+	template := &config.Template{
+		PostInitHooks: []*config.Hook{
+			{
+				Run: `trap 'exit 0' TERM; touch "$MARKER"; while true; do sleep 0.1; done`,
+				Env: map[string]string{"MARKER": started},
+			},
+			{Run: "echo never runs"},
+		},
+	}
+	s.interruptWhenStarted(started, syscall.SIGTERM, false)
+
+	out := &strings.Builder{}
+	err := template.ExecuteHooksWithOut(s.repoDir, config.PostInitHook, out)
+	s.Require().EqualError(err, "failed to execute hook "+template.PostInitHooks[0].String()+": stopped by terminated")
+	s.Require().NotContains(out.String(), "never runs")
+	s.Require().ErrorIs(err, config.Interrupted())
+}
+
+func (s *HookTestSuite) TestKillHooks() {
+	started := filepath.Join(s.repoDir, "started")
+	template := &config.Template{
+		PostInitHooks: []*config.Hook{
+			{
+				Run: `trap '' INT TERM; touch "$MARKER"; while true; do sleep 0.1; done`,
+				Env: map[string]string{"MARKER": started},
+			},
+		},
+	}
+	s.interruptWhenStarted(started, syscall.SIGINT, true)
+
+	start := time.Now()
+	err := template.ExecuteHooksWithOut(s.repoDir, config.PostInitHook, &strings.Builder{})
+	s.Require().ErrorContains(err, "stopped by interrupt: signal: killed")
+	s.Require().Less(time.Since(start), 3*time.Second)
+}
+
+func (s *HookTestSuite) TestOnFailureHooksRunAfterInterrupt() {
+	s.T().Cleanup(config.ResetInterrupt)
+	config.Interrupt(syscall.SIGINT)
+	template := &config.Template{
+		PreInitHooks:   []*config.Hook{{Run: "echo never runs"}},
+		OnFailureHooks: []*config.Hook{{Run: "echo cleaning up"}},
+	}
+
+	out := &strings.Builder{}
+	err := template.ExecuteHooksWithOut(s.repoDir, config.PreInitHook, out)
+	s.Require().EqualError(err, "failed to execute hook echo never runs: stopped by interrupt")
+	err = template.ExecuteHooksWithOut(s.repoDir, config.OnFailureHook, out)
+	s.Require().NoError(err)
+	s.Require().Equal("[echo cleaning up] cleaning up\n", out.String())
+}
+
+func (s *HookTestSuite) TestInterruptIgnoresFailurePolicy() {
+	for _, policy := range []config.FailurePolicy{config.WarnOnFailure, config.IgnoreFailure} {
+		s.Run(string(policy), func() {
+			started := filepath.Join(s.T().TempDir(), "started")
+			template := &config.Template{
+				PostInitHooks: []*config.Hook{
+					{
+						Run:           `touch "$MARKER"; while true; do sleep 0.1; done`,
+						Env:           map[string]string{"MARKER": started},
+						FailurePolicy: policy,
+					},
+					{Run: "echo never runs", FailurePolicy: policy},
+				},
+			}
+			s.interruptWhenStarted(started, syscall.SIGTERM, false)
+
+			out := &strings.Builder{}
+			err := template.ExecuteHooksWithOut(s.repoDir, config.PostInitHook, out)
+			s.Require().ErrorContains(err, "stopped by terminated")
+			s.Require().Empty(out.String())
+			config.ResetInterrupt()
+		})
+	}
+}
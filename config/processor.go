@@ -0,0 +1,190 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/scanner"
+	"io"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// GoFormatProcessor formats Go source code with go/format.
+	GoFormatProcessor = "gofmt"
+	// JSONProcessor normalizes JSON documents to two space indentation.
+	JSONProcessor = "json"
+	// YAMLProcessor normalizes YAML documents to two space indentation.
+	YAMLProcessor = "yaml"
+)
+
+// builtInProcessors maps the name of each built-in processor to the function that processes a file.
+var builtInProcessors = map[string]func([]byte) ([]byte, error){
+	GoFormatProcessor: format.Source,
+	JSONProcessor:     formatJSON,
+	YAMLProcessor:     formatYAML,
+}
+
+// Processor transforms rendered files that match a glob before they are written.
+type Processor struct {
+	// Glob is the glob path of the files to process. Required. The glob path is relative to Directory.
+	Glob string `yaml:"glob"`
+	// BuiltIn is the name of a built-in processor, one of gofmt, json or yaml. Either BuiltIn or Command is required.
+	BuiltIn string `yaml:"builtin,omitempty"`
+	// Command is a command that receives the rendered file on stdin and writes the processed file to stdout, e.g.
+	// "terraform fmt -". Either BuiltIn or Command is required. It is run with /bin/sh in the same way as a hook.
+	Command string `yaml:"command,omitempty"`
+}
+
+// String returns the name of the built-in processor or the command.
+func (p *Processor) String() string {
+	if len(p.BuiltIn) > 0 {
+		return p.BuiltIn
+	}
+	return p.Command
+}
+
+// validate ensures the processor has a valid glob and either a known built-in processor or a command.
+func (p *Processor) validate() error {
+	var errs []error
+	if !doublestar.ValidatePattern(p.Glob) {
+		errs = append(errs, fmt.Errorf("processor has an invalid glob %q", p.Glob))
+	}
+	switch {
+	case len(p.BuiltIn) > 0 && len(p.Command) > 0:
+		errs = append(errs, fmt.Errorf("processor for %s sets both a builtin and a command", p.Glob))
+	case len(p.BuiltIn) > 0:
+		if _, ok := builtInProcessors[p.BuiltIn]; !ok {
+			errs = append(errs, fmt.Errorf("processor for %s has an unknown builtin %s", p.Glob, p.BuiltIn))
+		}
+	case len(p.Command) == 0:
+		errs = append(errs, fmt.Errorf("processor for %s sets neither a builtin nor a command", p.Glob))
+	}
+	return errors.Join(errs...)
+}
+
+// ProcessFile runs the rendered content of the file at relPath, relative to Directory, through each processor whose
+// glob matches it in the order they are defined. If GoFormat is set, a .go file is formatted first. Command processors
+// are skipped if SkipHooks is set.
+func (t *Template) ProcessFile(repoDir, relPath string, content []byte) ([]byte, error) {
+	if t.GoFormat && filepath.Ext(relPath) == ".go" {
+		formatted, err := t.formatGo(relPath, content)
+		if err != nil {
+			return nil, err
+		}
+		content = formatted
+	}
+
+	for _, p := range t.Processors {
+		matched, err := doublestar.Match(p.Glob, filepath.ToSlash(relPath))
+		if err != nil {
+			return nil, fmt.Errorf("invalid processor glob %q: %w", p.Glob, err)
+		}
+		if !matched {
+			continue
+		}
+
+		if p.BuiltIn == GoFormatProcessor {
+			// Syntax errors are reported the same way as for GoFormat.
+			content, err = t.formatGo(relPath, content)
+			if err != nil {
+				return nil, err
+			}
+		} else if len(p.BuiltIn) > 0 {
+			content, err = builtInProcessors[p.BuiltIn](content)
+		} else if t.SkipHooks {
+			slog.Debug("skipping processor", slog.String("path", relPath), slog.String("command", p.Command))
+			continue
+		} else {
+			content, err = t.runProcessor(repoDir, p, content)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("processor %s failed on %s: %w", p, relPath, err)
+		}
+		slog.Debug("processed file", slog.String("path", relPath), slog.String("processor", p.String()))
+	}
+	return content, nil
+}
+
+func (t *Template) runProcessor(repoDir string, p *Processor, content []byte) ([]byte, error) {
+	hook := &Hook{Run: p.Command}
+	env := t.hookEnviron()
+	var stdout, stderr bytes.Buffer
+	err := hook.run(interruptContext, repoDir, func(cmd *exec.Cmd) {
+		cmd.Env = env
+		cmd.Stdin = bytes.NewReader(content)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+	})
+	if err != nil {
+		if output := strings.TrimSpace(stderr.String()); len(output) > 0 {
+			return nil, fmt.Errorf("%w\n%s", err, output)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+func formatJSON(content []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, bytes.TrimSpace(content), "", "  "); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+func formatYAML(content []byte) ([]byte, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := encoder.Encode(&doc); err != nil {
+			return nil, err
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formatGo formats the rendered content of the Go file at relPath, relative to Directory.
+func (t *Template) formatGo(relPath string, content []byte) ([]byte, error) {
+	formatted, err := format.Source(content)
+	if err != nil {
+		return nil, goFormatError(filepath.Join(t.Directory, relPath), content, err)
+	}
+	return formatted, nil
+}
+
+// goFormatError describes a rendered Go file that could not be formatted. The rendered line with the first syntax error
+// is included as its line number does not match the template when the template has actions.
+func goFormatError(templatePath string, content []byte, err error) error {
+	var errList scanner.ErrorList
+	if errors.As(err, &errList) && len(errList) > 0 {
+		lines := strings.Split(string(content), "\n")
+		if line := errList[0].Pos.Line; line > 0 && line <= len(lines) {
+			return fmt.Errorf("rendered template %s is not valid Go: %w\nrendered line %d: %s",
+				templatePath, err, line, strings.TrimSpace(lines[line-1]))
+		}
+	}
+	return fmt.Errorf("rendered template %s is not valid Go: %w", templatePath, err)
+}
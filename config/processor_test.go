@@ -0,0 +1,165 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rogueserenity/stenciler/config"
+)
+
+type ProcessorTestSuite struct {
+	suite.Suite
+
+	repoDir string
+}
+
+func TestProcessorTestSuite(t *testing.T) {
+	suite.Run(t, new(ProcessorTestSuite))
+}
+
+func (s *ProcessorTestSuite) SetupTest() {
+	s.repoDir = s.T().TempDir()
+}
+
+func (s *ProcessorTestSuite) TestYAML() {
+	template := &config.Template{
+		Processors: []*config.Processor{{Glob: "**/*.yaml", BuiltIn: config.YAMLProcessor}},
+	}
+	content := "ship:   serenity # firefly\ncrew:\n    - mal\n    - zoe\n---\nship: alliance\n"
+
+	actual, err := template.ProcessFile(s.repoDir, "deploy/values.yaml", []byte(content))
+	s.Require().NoError(err)
+	s.Require().Equal("ship: serenity # firefly\ncrew:\n  - mal\n  - zoe\n---\nship: alliance\n", string(actual))
+}
+
+func (s *ProcessorTestSuite) TestInvalidJSON() {
+	template := &config.Template{
+		Processors: []*config.Processor{{Glob: "*.json", BuiltIn: config.JSONProcessor}},
+	}
+
+	_, err := template.ProcessFile(s.repoDir, "config.json", []byte(`{"ship": }`))
+	s.Require().ErrorContains(err, "processor json failed on config.json:")
+}
+
+func (s *ProcessorTestSuite) TestNoMatch() {
+	template := &config.Template{
+		Processors: []*config.Processor{{Glob: "*.json", BuiltIn: config.JSONProcessor}},
+	}
+
+	actual, err := template.ProcessFile(s.repoDir, "nested/config.json", []byte("not json"))
+	s.Require().NoError(err)
+	s.Require().Equal("not json", string(actual))
+}
+
+func (s *ProcessorTestSuite) TestCommandsInOrder() {
+	template := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:  "suffix",
+				Value: "!",
+			},
+		},
+		Processors: []*config.Processor{
+			{Glob: "*.txt", Command: "tr a-z A-Z"},
+			{Glob: "*.txt", Command: `cat; printf "%s" "$STENCILER_SUFFIX"`},
+		},
+	}
+
+	actual, err := template.ProcessFile(s.repoDir, "ship.txt", []byte("serenity"))
+	s.Require().NoError(err)
+	s.Require().Equal("SERENITY!", string(actual))
+}
+
+func (s *ProcessorTestSuite) TestFailingCommand() {
+	template := &config.Template{
+		Processors: []*config.Processor{{Glob: "*.txt", Command: "echo broken >&2; exit 2"}},
+	}
+
+	_, err := template.ProcessFile(s.repoDir, "ship.txt", []byte("serenity"))
+	s.Require().ErrorContains(err, "processor echo broken >&2; exit 2 failed on ship.txt: exit status 2\nbroken")
+}
+
+func (s *ProcessorTestSuite) TestSkipHooksSkipsCommands() {
+	template := &config.Template{
+		SkipHooks: true,
+		Processors: []*config.Processor{
+			{Glob: "*.txt", Command: "exit 1"},
+			{Glob: "*.json", BuiltIn: config.JSONProcessor},
+		},
+	}
+
+	actual, err := template.ProcessFile(s.repoDir, "ship.txt", []byte("serenity"))
+	s.Require().NoError(err)
+	s.Require().Equal("serenity", string(actual))
+
+	actual, err = template.ProcessFile(s.repoDir, "ship.json", []byte(`{"a":1}`))
+	s.Require().NoError(err)
+	s.Require().Equal("{\n  \"a\": 1\n}\n", string(actual))
+}
+
+func (s *ProcessorTestSuite) TestValidate() {
+	template := &config.Template{
+		Processors: []*config.Processor{
+			{Glob: "[*.go", BuiltIn: config.GoFormatProcessor},
+			{Glob: "*.go", BuiltIn: config.GoFormatProcessor, Command: "gofmt"},
+			{Glob: "*.toml", BuiltIn: "toml"},
+			{Glob: "*.txt"},
+		},
+	}
+
+	err := template.Validate(s.repoDir)
+	s.Require().ErrorContains(err, `processor has an invalid glob "[*.go"`)
+	s.Require().ErrorContains(err, "processor for *.go sets both a builtin and a command")
+	s.Require().ErrorContains(err, "processor for *.toml has an unknown builtin toml")
+	s.Require().ErrorContains(err, "processor for *.txt sets neither a builtin nor a command")
+}
+
+func (s *ProcessorTestSuite) TestGoFormat() {
+	template := &config.Template{
+		Directory: "root",
+		GoFormat:  true,
+	}
+	content := "package main\n\n\n\ntype ship struct {\nName string\n    Crew int\n}\n"
+
+	actual, err := template.ProcessFile(s.repoDir, "ship.go", []byte(content))
+	s.Require().NoError(err)
+	s.Require().Equal("package main\n\ntype ship struct {\n\tName string\n\tCrew int\n}\n", string(actual))
+
+	actual, err = template.ProcessFile(s.repoDir, "ship.md", []byte(content))
+	s.Require().NoError(err)
+	s.Require().Equal(content, string(actual))
+}
+
+func (s *ProcessorTestSuite) TestGoFormatSyntaxError() {
+	templates := map[string]*config.Template{
+		"go-format": {
+			Directory: "root",
+			GoFormat:  true,
+		},
+		"gofmt processor": {
+			Directory:  "root",
+			Processors: []*config.Processor{{Glob: "**/*.go", BuiltIn: config.GoFormatProcessor}},
+		},
+	}
+	content := "package main\n\nfunc main() {\n\tfmt.Println(\"hi\"\n}\n"
+
+	for name, template := range templates {
+		s.Run(name, func() {
+			_, err := template.ProcessFile(s.repoDir, "cmd/main.go", []byte(content))
+			s.Require().ErrorContains(err, "rendered template root/cmd/main.go is not valid Go: 4:")
+			s.Require().ErrorContains(err, "rendered line 4: fmt.Println(\"hi\"")
+		})
+	}
+}
+
+func (s *ProcessorTestSuite) TestGoFormatBeforeProcessors() {
+	template := &config.Template{
+		GoFormat:   true,
+		Processors: []*config.Processor{{Glob: "*.go", Command: "grep -c '^\t'"}},
+	}
+
+	actual, err := template.ProcessFile(s.repoDir, "main.go", []byte("package main\nfunc main() {\nprintln()\n}\n"))
+	s.Require().NoError(err)
+	s.Require().Equal("1\n", string(actual))
+}
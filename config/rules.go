@@ -0,0 +1,127 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// CheckRules checks the value of the parameter against its declarative rules. All rule failures are returned joined
+// together in a *ValidationError. An empty value that is not required skips the remaining rules.
+func (p *Param) CheckRules() error {
+	if len(p.Value) == 0 {
+		if p.Required {
+			return &ValidationError{Param: p.Name, Err: fmt.Errorf("a value for %s is required", p.Name)}
+		}
+		return nil
+	}
+
+	var errs []error
+	if len(p.Pattern) > 0 {
+		re, err := compilePattern(p.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern for %s: %w", p.Name, err)
+		}
+		if !re.MatchString(p.Value) {
+			errs = append(errs, fmt.Errorf("value %q for %s must match the pattern %s", p.DisplayValue(), p.Name, p.Pattern))
+		}
+	}
+
+	length := utf8.RuneCountInString(p.Value)
+	if p.MinLength > 0 && length < p.MinLength {
+		errs = append(errs, fmt.Errorf("value %q for %s must be at least %d characters long",
+			p.DisplayValue(), p.Name, p.MinLength))
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		errs = append(errs, fmt.Errorf("value %q for %s must be at most %d characters long",
+			p.DisplayValue(), p.Name, p.MaxLength))
+	}
+
+	if p.Min != nil || p.Max != nil {
+		errs = append(errs, p.checkRange())
+	}
+
+	if len(p.AllowedValues) > 0 && !slices.Contains(p.AllowedValues, p.Value) {
+		errs = append(errs, fmt.Errorf("value %q for %s must be one of: %s",
+			p.DisplayValue(), p.Name, strings.Join(p.AllowedValues, ", ")))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return &ValidationError{Param: p.Name, Err: err}
+	}
+	return nil
+}
+
+// Rules returns a human readable description of each declarative rule defined for the parameter.
+func (p *Param) Rules() []string {
+	var rules []string
+	if p.Required {
+		rules = append(rules, "required")
+	}
+	if len(p.Pattern) > 0 {
+		rules = append(rules, "must match "+p.Pattern)
+	}
+	if p.MinLength > 0 {
+		rules = append(rules, fmt.Sprintf("at least %d characters", p.MinLength))
+	}
+	if p.MaxLength > 0 {
+		rules = append(rules, fmt.Sprintf("at most %d characters", p.MaxLength))
+	}
+	if p.Min != nil {
+		rules = append(rules, "at least "+formatNumber(*p.Min))
+	}
+	if p.Max != nil {
+		rules = append(rules, "at most "+formatNumber(*p.Max))
+	}
+	if len(p.AllowedValues) > 0 {
+		rules = append(rules, "one of: "+strings.Join(p.AllowedValues, ", "))
+	}
+	return rules
+}
+
+func (p *Param) checkRange() error {
+	n, err := strconv.ParseFloat(p.Value, 64)
+	if err != nil {
+		return fmt.Errorf("value %q for %s must be a number", p.DisplayValue(), p.Name)
+	}
+	if p.Min != nil && n < *p.Min {
+		return fmt.Errorf("value %q for %s must be at least %s", p.DisplayValue(), p.Name, formatNumber(*p.Min))
+	}
+	if p.Max != nil && n > *p.Max {
+		return fmt.Errorf("value %q for %s must be at most %s", p.DisplayValue(), p.Name, formatNumber(*p.Max))
+	}
+	return nil
+}
+
+// validateRules ensures that the declarative rules for the parameter are well formed.
+func (p *Param) validateRules() error {
+	var errs []error
+	if len(p.Pattern) > 0 {
+		if _, err := compilePattern(p.Pattern); err != nil {
+			errs = append(errs, fmt.Errorf("param %s has an invalid pattern: %w", p.Name, err))
+		}
+	}
+	if p.MinLength < 0 || p.MaxLength < 0 {
+		errs = append(errs, fmt.Errorf("param %s has a negative length limit", p.Name))
+	}
+	if p.MaxLength > 0 && p.MinLength > p.MaxLength {
+		errs = append(errs, fmt.Errorf("param %s has a min-length greater than its max-length", p.Name))
+	}
+	if p.Min != nil && p.Max != nil && *p.Min > *p.Max {
+		errs = append(errs, fmt.Errorf("param %s has a min greater than its max", p.Name))
+	}
+	return errors.Join(errs...)
+}
+
+// compilePattern compiles the pattern so that it must match the entire value.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile(`^(?:` + pattern + `)$`)
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
@@ -0,0 +1,162 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rogueserenity/stenciler/config"
+)
+
+type RulesTestSuite struct {
+	suite.Suite
+}
+
+func TestRulesTestSuite(t *testing.T) {
+	suite.Run(t, new(RulesTestSuite))
+}
+
+func float(f float64) *float64 {
+	return &f
+}
+
+func (s *RulesTestSuite) TestNoRules() {
+	param := &config.Param{
+		Name:  "test",
+		Value: "value",
+	}
+	s.Require().NoError(param.CheckRules())
+	s.Require().Empty(param.Rules())
+}
+
+func (s *RulesTestSuite) TestRequired() {
+	param := &config.Param{
+		Name:     "test",
+		Required: true,
+	}
+	s.Require().EqualError(param.CheckRules(), "a value for test is required")
+
+	param.Value = "value"
+	s.Require().NoError(param.CheckRules())
+}
+
+func (s *RulesTestSuite) TestEmptyValueSkipsRules() {
+	param := &config.Param{
+		Name:      "test",
+		Pattern:   "[a-z]+",
+		MinLength: 3,
+	}
+	s.Require().NoError(param.CheckRules())
+}
+
+func (s *RulesTestSuite) TestPattern() {
+	param := &config.Param{
+		Name:    "test",
+		Pattern: "[a-z]+",
+		Value:   "abc1",
+	}
+	s.Require().EqualError(param.CheckRules(), `value "abc1" for test must match the pattern [a-z]+`)
+
+	param.Value = "abc"
+	s.Require().NoError(param.CheckRules())
+}
+
+func (s *RulesTestSuite) TestLength() {
+	param := &config.Param{
+		Name:      "test",
+		MinLength: 2,
+		MaxLength: 4,
+		Value:     "a",
+	}
+	s.Require().EqualError(param.CheckRules(), `value "a" for test must be at least 2 characters long`)
+
+	param.Value = "abcde"
+	s.Require().EqualError(param.CheckRules(), `value "abcde" for test must be at most 4 characters long`)
+
+	param.Value = "abc"
+	s.Require().NoError(param.CheckRules())
+}
+
+func (s *RulesTestSuite) TestRange() {
+	param := &config.Param{
+		Name:  "test",
+		Min:   float(0),
+		Max:   float(10.5),
+		Value: "ten",
+	}
+	s.Require().EqualError(param.CheckRules(), `value "ten" for test must be a number`)
+
+	param.Value = "-1"
+	s.Require().EqualError(param.CheckRules(), `value "-1" for test must be at least 0`)
+
+	param.Value = "11"
+	s.Require().EqualError(param.CheckRules(), `value "11" for test must be at most 10.5`)
+
+	param.Value = "10"
+	s.Require().NoError(param.CheckRules())
+}
+
+func (s *RulesTestSuite) TestAllowedValues() {
+	param := &config.Param{
+		Name:          "test",
+		AllowedValues: []string{"foo", "bar"},
+		Value:         "baz",
+	}
+	s.Require().EqualError(param.CheckRules(), `value "baz" for test must be one of: foo, bar`)
+
+	param.Value = "bar"
+	s.Require().NoError(param.CheckRules())
+}
+
+func (s *RulesTestSuite) TestMultipleFailures() {
+	param := &config.Param{
+		Name:      "test",
+		Pattern:   "[0-9]+",
+		MaxLength: 2,
+		Value:     "abc",
+	}
+	err := param.CheckRules()
+	s.Require().ErrorContains(err, `value "abc" for test must match the pattern [0-9]+`)
+	s.Require().ErrorContains(err, `value "abc" for test must be at most 2 characters long`)
+}
+
+func (s *RulesTestSuite) TestRules() {
+	param := &config.Param{
+		Name:          "test",
+		Required:      true,
+		Pattern:       "[a-z]+",
+		MinLength:     1,
+		MaxLength:     8,
+		Min:           float(1),
+		Max:           float(2.5),
+		AllowedValues: []string{"a", "b"},
+	}
+	expected := []string{
+		"required",
+		"must match [a-z]+",
+		"at least 1 characters",
+		"at most 8 characters",
+		"at least 1",
+		"at most 2.5",
+		"one of: a, b",
+	}
+	s.Require().Equal(expected, param.Rules())
+}
+
+func (s *RulesTestSuite) TestValidateRunsRules() {
+	param := &config.Param{
+		Name:     "test",
+		Required: true,
+	}
+	s.Require().EqualError(param.Validate("test-repo"), "a value for test is required")
+}
+
+func (s *RulesTestSuite) TestSecretValueNotInMessage() {
+	param := &config.Param{
+		Name:      "test",
+		MinLength: 10,
+		Secret:    true,
+		Value:     "hunter2",
+	}
+	s.Require().EqualError(param.CheckRules(), `value "[REDACTED]" for test must be at least 10 characters long`)
+}
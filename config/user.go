@@ -0,0 +1,116 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	userConfigDirName  = "stenciler"
+	userConfigFileName = "config.yaml"
+)
+
+// UserConfig is the per-user configuration shared across all templates.
+type UserConfig struct {
+	// Defaults holds default values keyed by param name. They are offered for any template param with a matching
+	// name.
+	Defaults map[string]string `yaml:"defaults,omitempty"`
+	// TrustedRepositories is a list of template repositories whose hooks are run without asking. Each entry is either
+	// a repository URL or a pattern such as https://github.com/example/* as accepted by path.Match.
+	TrustedRepositories []string `yaml:"trusted-repositories,omitempty"`
+	// RestrictHookEnv is true if hooks of every template are run with a restricted environment, even if the template
+	// does not ask for it.
+	RestrictHookEnv bool `yaml:"restrict-hook-env,omitempty"`
+}
+
+// UserConfigPath returns the path of the user config file within the user's config directory, e.g.
+// $XDG_CONFIG_HOME/stenciler/config.yaml.
+func UserConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, userConfigDirName, userConfigFileName), nil
+}
+
+// ReadUserConfig attempts to read the user config file. An empty config is returned if the file does not exist.
+func ReadUserConfig() (*UserConfig, error) {
+	userConfigPath, err := UserConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(userConfigPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &UserConfig{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadUserConfigFrom(file)
+}
+
+// ReadUserConfigFrom attempts to read a user config from the specified reader.
+func ReadUserConfigFrom(in io.Reader) (*UserConfig, error) {
+	b, err := io.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+	userConfig := &UserConfig{}
+	err = yaml.Unmarshal(b, userConfig)
+	if err != nil {
+		return nil, err
+	}
+	return userConfig, nil
+}
+
+// WriteUserConfig attempts to write the user config file, creating its directory if needed.
+func WriteUserConfig(userConfig *UserConfig) error {
+	userConfigPath, err := UserConfigPath()
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(userConfigPath), 0755)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(userConfigPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return userConfig.Write(file)
+}
+
+// Write attempts to write the user config to the specified writer.
+func (c *UserConfig) Write(out io.Writer) error {
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(b)
+	return err
+}
+
+// Trusts returns true if the hooks of the repository may be run without asking.
+func (c *UserConfig) Trusts(repository string) bool {
+	for _, pattern := range c.TrustedRepositories {
+		if matched, err := path.Match(pattern, repository); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Trust adds the repository to the trusted repositories if it is not already trusted.
+func (c *UserConfig) Trust(repository string) {
+	if !c.Trusts(repository) {
+		c.TrustedRepositories = append(c.TrustedRepositories, repository)
+	}
+}
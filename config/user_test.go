@@ -0,0 +1,87 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rogueserenity/stenciler/config"
+)
+
+type UserConfigTestSuite struct {
+	suite.Suite
+
+	configHome string
+}
+
+func TestUserConfigTestSuite(t *testing.T) {
+	suite.Run(t, new(UserConfigTestSuite))
+}
+
+func (s *UserConfigTestSuite) SetupTest() {
+	s.configHome = s.T().TempDir()
+	s.T().Setenv("XDG_CONFIG_HOME", s.configHome)
+}
+
+func (s *UserConfigTestSuite) TestUserConfigPath() {
+	path, err := config.UserConfigPath()
+	s.Require().NoError(err)
+	s.Require().Equal(filepath.Join(s.configHome, "stenciler", "config.yaml"), path)
+}
+
+func (s *UserConfigTestSuite) TestReadUserConfigMissing() {
+	userConfig, err := config.ReadUserConfig()
+	s.Require().NoError(err)
+	s.Require().Empty(userConfig.Defaults)
+}
+
+func (s *UserConfigTestSuite) TestReadUserConfig() {
+	dir := filepath.Join(s.configHome, "stenciler")
+	s.Require().NoError(os.MkdirAll(dir, 0755))
+	err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("defaults:\n  author: Malcolm Reynolds\n"), 0644)
+	s.Require().NoError(err)
+
+	userConfig, err := config.ReadUserConfig()
+	s.Require().NoError(err)
+	s.Require().Equal(map[string]string{"author": "Malcolm Reynolds"}, userConfig.Defaults)
+}
+
+func (s *UserConfigTestSuite) TestReadUserConfigFromInvalid() {
+	_, err := config.ReadUserConfigFrom(strings.NewReader("defaults: [foo"))
+	s.Require().Error(err)
+}
+
+func (s *UserConfigTestSuite) TestWriteUserConfig() {
+	userConfig := &config.UserConfig{
+		Defaults: map[string]string{"author": "Malcolm Reynolds"},
+	}
+	userConfig.Trust("https://github.com/browncoats/template.git")
+
+	err := config.WriteUserConfig(userConfig)
+	s.Require().NoError(err)
+
+	actual, err := config.ReadUserConfig()
+	s.Require().NoError(err)
+	s.Require().Equal(userConfig, actual)
+}
+
+func (s *UserConfigTestSuite) TestTrusts() {
+	userConfig := &config.UserConfig{
+		TrustedRepositories: []string{
+			"https://github.com/browncoats/*",
+			"https://github.com/alliance/template.git",
+		},
+	}
+	s.Require().True(userConfig.Trusts("https://github.com/browncoats/template.git"))
+	s.Require().True(userConfig.Trusts("https://github.com/alliance/template.git"))
+	s.Require().False(userConfig.Trusts("https://github.com/alliance/other.git"))
+	s.Require().False(userConfig.Trusts("https://github.com/browncoats/nested/template.git"))
+
+	userConfig.Trust("https://github.com/browncoats/serenity.git")
+	s.Require().Len(userConfig.TrustedRepositories, 2)
+	userConfig.Trust("https://github.com/alliance/other.git")
+	s.Require().Len(userConfig.TrustedRepositories, 3)
+}
@@ -1,18 +1,35 @@
 package config
 
-import (
-	"errors"
-	"fmt"
-	"os"
-	"path/filepath"
-)
-
-// Validate validates all the hooks in the template exist and are executable.
+import "errors"
+
+// Validate validates all the hooks and processors in the template are well formed and that hook scripts exist and are
+// executable, that no parameter uses a reserved name and that the declarative rules, migrations and default sources on
+// each parameter are well formed.
 func (t *Template) Validate(repoPath string) error {
 	var errs []error
-	hookPaths := t.gatherHookPaths()
-	for _, hookPath := range hookPaths {
-		if err := validateHook(hookPath, repoPath); err != nil {
+	for _, param := range t.Params {
+		if err := param.validateName(); err != nil {
+			errs = append(errs, err)
+		}
+		if err := param.validateRules(); err != nil {
+			errs = append(errs, err)
+		}
+		if err := param.validateMigrations(); err != nil {
+			errs = append(errs, err)
+		}
+		if err := param.validateDefaultSources(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, processor := range t.Processors {
+		if err := processor.validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, hook := range t.gatherHooks() {
+		if err := hook.validate(repoPath); err != nil {
 			errs = append(errs, err)
 		}
 	}
@@ -23,35 +40,20 @@ func (t *Template) Validate(repoPath string) error {
 	return nil
 }
 
-func (t *Template) gatherHookPaths() []string {
-	var hookPaths []string
+func (t *Template) gatherHooks() []*Hook {
+	var hooks []*Hook
 
 	for _, param := range t.Params {
-		if param.ValidationHook != "" {
-			hookPaths = append(hookPaths, param.ValidationHook)
+		if param.ValidationHook != nil {
+			hooks = append(hooks, param.ValidationHook)
 		}
 	}
 
-	hookPaths = append(hookPaths, t.PreInitHookPaths...)
-	hookPaths = append(hookPaths, t.PostInitHookPaths...)
-	hookPaths = append(hookPaths, t.PreUpdateHookPaths...)
-	hookPaths = append(hookPaths, t.PostUpdateHookPaths...)
-
-	return hookPaths
-}
-
-func validateHook(hookPath, repoPath string) error {
-	path := filepath.Join(repoPath, hookPath)
-
-	info, err := os.Stat(path)
-	if err != nil {
-		return fmt.Errorf("hook %s does not exist", hookPath)
-	}
-
-	mode := info.Mode()
-	if !mode.IsRegular() || mode.Perm()&0111 == 0 {
-		return fmt.Errorf("hook %s is not executable", hookPath)
-	}
+	hooks = append(hooks, t.PreInitHooks...)
+	hooks = append(hooks, t.PostInitHooks...)
+	hooks = append(hooks, t.PreUpdateHooks...)
+	hooks = append(hooks, t.PostUpdateHooks...)
+	hooks = append(hooks, t.OnFailureHooks...)
 
-	return nil
+	return hooks
 }
@@ -25,21 +25,21 @@ func (s *ValidateTestSuite) SetupTest() {
 		{
 			Params: []*config.Param{
 				{
-					ValidationHook: "invalid-hook",
+					ValidationHook: &config.Hook{Path: "invalid-hook"},
 				},
 			},
 		},
 		{
-			PreInitHookPaths: []string{"invalid-hook"},
+			PreInitHooks: []*config.Hook{{Path: "invalid-hook"}},
 		},
 		{
-			PostInitHookPaths: []string{"invalid-hook"},
+			PostInitHooks: []*config.Hook{{Path: "invalid-hook"}},
 		},
 		{
-			PreUpdateHookPaths: []string{"invalid-hook"},
+			PreUpdateHooks: []*config.Hook{{Path: "invalid-hook"}},
 		},
 		{
-			PostUpdateHookPaths: []string{"invalid-hook"},
+			PostUpdateHooks: []*config.Hook{{Path: "invalid-hook"}},
 		},
 	}
 }
@@ -83,3 +83,51 @@ func (s *ValidateTestSuite) TestValidateWithValidHooks() {
 		s.Require().NoError(err)
 	}
 }
+
+func (s *ValidateTestSuite) TestValidateWithInvalidRules() {
+	minimum, maximum := 5.0, 1.0
+	template := config.Template{
+		Params: []*config.Param{
+			{
+				Name:    "pattern",
+				Pattern: "[a-z",
+			},
+			{
+				Name:      "length",
+				MinLength: 5,
+				MaxLength: 1,
+			},
+			{
+				Name: "range",
+				Min:  &minimum,
+				Max:  &maximum,
+			},
+		},
+	}
+
+	err := template.Validate("test-repo")
+	s.Require().ErrorContains(err, "param pattern has an invalid pattern")
+	s.Require().ErrorContains(err, "param length has a min-length greater than its max-length")
+	s.Require().ErrorContains(err, "param range has a min greater than its max")
+}
+
+func (s *ValidateTestSuite) TestValidateWithInvalidMigrations() {
+	template := config.Template{
+		Params: []*config.Param{
+			{
+				Name:     "param1",
+				Revision: 1,
+				Migrations: []*config.Migration{
+					{
+						Revision:  2,
+						Transform: "{{ .Value",
+					},
+				},
+			},
+		},
+	}
+
+	err := template.Validate("test-repo")
+	s.Require().ErrorContains(err, "param param1 has a migration to revision 2 outside of 1 to 1")
+	s.Require().ErrorContains(err, "param param1 has an invalid migration transform")
+}
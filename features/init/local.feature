@@ -59,3 +59,9 @@ Feature: Local Template Processing
     Given I have a local template with a pre-init hook that uses parameter variables
     When I run stenciler init with the repository URL in an empty directory
     Then I see the current directory initialized with the template data
+
+  Scenario: Ensuring that a pre-init hook can read the config file and publish outputs
+    Given I have a local template with a pre-init hook that publishes outputs
+    When I run stenciler init with the repository URL in an empty directory
+    Then I see the current directory initialized with the template data
+    And I see the hook outputs for params recorded in the config file
@@ -0,0 +1,7 @@
+@params
+Feature: Local Template Params
+
+  Scenario: Listing the params of a template as JSON
+    Given I have a local template with prompted and internal params
+    When I run stenciler params with JSON output
+    Then I see the params of the template without secret values
@@ -877,6 +877,61 @@ def step_impl(
         yaml.dump(yaml_data, f)
 
 
+@given("I have a local updated template with a recorded value")
+def step_impl(
+    context: Context,
+):
+    context.repository_url = "https://github.com/local/repo"
+    context.template_root_dir = "foo"
+    root = os.path.join(context.input_dir.name, context.template_root_dir)
+    leaf_dir1 = os.path.join(root, "bar", "baz")
+    os.makedirs(leaf_dir1, exist_ok=True)
+    with open(os.path.join(leaf_dir1, "file.txt"), "w", encoding="utf-8") as f:
+        f.write("Rogue{{.ship}}\n")
+
+    # Accept the offered default, which is the recorded value rather than the template
+    # default.
+    context.prompts = {
+        "What is the name of your ship?": "",
+    }
+
+    yaml_data = {
+        "templates": [
+            {
+                "directory": "foo",
+                "params": [
+                    {
+                        "name": "ship",
+                        "prompt": "What is the name of your ship?",
+                        "default": "Firefly",
+                    },
+                ],
+            },
+        ],
+    }
+    with open(context.input_config_file, "w", encoding="utf-8") as f:
+        yaml.dump(yaml_data, f)
+
+    yaml_data = {
+        "templates": [
+            {
+                "repository": context.repository_url,
+                "directory": "foo",
+                "params": [
+                    {
+                        "name": "ship",
+                        "prompt": "What is the name of your ship?",
+                        "default": "Firefly",
+                        "value": "Serenity",
+                    },
+                ],
+            },
+        ],
+    }
+    with open(context.output_config_file, "w", encoding="utf-8") as f:
+        yaml.dump(yaml_data, f)
+
+
 @given("I have a local template with a pre-init hook that uses parameter variables")
 def step_impl(
     context: Context,
@@ -921,6 +976,53 @@ def step_impl(
         f.write("Malcolm Reynolds\n")
 
 
+@given("I have a local template with a pre-init hook that publishes outputs")
+def step_impl(
+    context: Context,
+):
+    context.repository_url = "https://github.com/local/repo"
+    context.template_root_dir = "foo"
+    root = os.path.join(context.input_dir.name, context.template_root_dir)
+    os.makedirs(root, exist_ok=True)
+    with open(os.path.join(root, "crew.txt"), "w", encoding="utf-8") as f:
+        f.write("{{.ship}}: {{.captain}}\n")
+
+    hooks_dir = os.path.join(context.input_dir.name, "hooks")
+    os.makedirs(hooks_dir, exist_ok=True)
+    hook = os.path.join(hooks_dir, "publish.sh")
+    with open(hook, "w", encoding="utf-8") as f:
+        f.write(
+            "grep -q 'directory: foo' .stenciler.yaml || exit 1\n"
+            'echo "ship=Serenity" >> "$STENCILER_BUILTIN_OUTPUT"\n'
+            'echo "captain=Malcolm Reynolds" >> "$STENCILER_BUILTIN_OUTPUT"\n'
+        )
+    os.chmod(hook, 0o755)
+
+    yaml_data = {
+        "templates": [
+            {
+                "directory": "foo",
+                "params": [
+                    {
+                        "name": "ship",
+                    },
+                ],
+                "pre-init-hooks": [
+                    "hooks/publish.sh",
+                ],
+            },
+        ],
+    }
+    with open(context.input_config_file, "w", encoding="utf-8") as f:
+        yaml.dump(yaml_data, f)
+
+    context.expected_params = {"ship": "Serenity"}
+    with open(
+        os.path.join(context.expected_dir.name, "crew.txt"), "w", encoding="utf-8"
+    ) as f:
+        f.write("Serenity: Malcolm Reynolds\n")
+
+
 @given("I have a local updated template with init-only files")
 def step_impl(
     context: Context,
@@ -972,3 +1074,66 @@ def step_impl(
     }
     with open(context.output_config_file, "w", encoding="utf-8") as f:
         yaml.dump(yaml_data, f)
+
+
+@given("I have a local template with prompted and internal params")
+def step_impl(
+    context: Context,
+):
+    context.template_root_dir = "foo"
+    os.makedirs(os.path.join(context.input_dir.name, context.template_root_dir))
+
+    yaml_data = {
+        "templates": [
+            {
+                "directory": "foo",
+                "params": [
+                    {
+                        "name": "ship",
+                        "prompt": "What is the name of your ship?",
+                        "default": "Serenity",
+                        "required": True,
+                    },
+                    {
+                        "name": "port",
+                        "value": "8080",
+                    },
+                    {
+                        "name": "token",
+                        "value": "shiny",
+                        "secret": True,
+                    },
+                ],
+            },
+        ],
+    }
+    with open(context.input_config_file, "w", encoding="utf-8") as f:
+        yaml.dump(yaml_data, f)
+
+    context.expected_params = [
+        {
+            "directory": "foo",
+            "params": [
+                {
+                    "name": "ship",
+                    "type": "string",
+                    "internal": False,
+                    "prompt": "What is the name of your ship?",
+                    "default": "Serenity",
+                    "required": True,
+                    "rules": ["required"],
+                },
+                {
+                    "name": "port",
+                    "type": "string",
+                    "internal": True,
+                    "value": "8080",
+                },
+                {
+                    "name": "token",
+                    "type": "secret",
+                    "internal": True,
+                },
+            ],
+        },
+    ]
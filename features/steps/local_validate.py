@@ -1,6 +1,8 @@
 import filecmp
+import json
 import os
 
+import yaml
 from behave import then
 from behave.runner import Context
 
@@ -39,3 +41,39 @@ def step_impl(
     )
     dcmp.report_full_closure()
     verify_same(dcmp)
+
+
+@then("I see the hook outputs for params recorded in the config file")
+def step_impl(
+    context: Context,
+):
+    with open(context.output_config_file, "r", encoding="utf-8") as f:
+        config = yaml.safe_load(f)
+    params = {p["name"]: p.get("value") for p in config["templates"][0]["params"]}
+    assert params == context.expected_params, params
+
+
+@then('I see the ship "{ship}" in the current directory')
+def step_impl(
+    context: Context,
+    ship: str,
+):
+    with open(
+        os.path.join(context.output_dir.name, "bar", "baz", "file.txt"),
+        "r",
+        encoding="utf-8",
+    ) as f:
+        assert f.read() == f"Rogue{ship}\n"
+    with open(context.output_config_file, "r", encoding="utf-8") as f:
+        config = yaml.safe_load(f)
+    params = {p["name"]: p.get("value") for p in config["templates"][0]["params"]}
+    assert params["ship"] == ship, params
+
+
+@then("I see the params of the template without secret values")
+def step_impl(
+    context: Context,
+):
+    assert "shiny" not in context.params_output, context.params_output
+    params = json.loads(context.params_output)
+    assert params == context.expected_params, params
@@ -25,7 +25,7 @@ def step_impl(
     context: Context,
 ):
     stenciler = os.path.join(os.getcwd(), "stenciler")
-    command = [stenciler, "init"]
+    command = [stenciler, "init", "--allow-hooks"]
     assert context.repository_url is not None, "context.repository_url must be provided"
     command.append(context.repository_url)
 
@@ -68,12 +68,9 @@ def step_impl(
     assert init.returncode == 0
 
 
-@when("I run stenciler update in the current directory")
-def step_impl(
-    context: Context,
-):
+def run_in_current_directory(context: Context, args: list[str]):
     stenciler = os.path.join(os.getcwd(), "stenciler")
-    command = [stenciler, "update"]
+    command = [stenciler] + args + ["--allow-hooks"]
 
     if context.auth_token is not None:
         command.append("-t")
@@ -112,3 +109,47 @@ def step_impl(
     print("STDOUT: ", update.stdout.read())
     print("STDERR: ", update.stderr.read())
     assert update.returncode == 0
+
+
+@when("I run stenciler update in the current directory")
+def step_impl(
+    context: Context,
+):
+    run_in_current_directory(context, ["update"])
+
+
+@when('I run stenciler update with "{args}" in the current directory')
+def step_impl(
+    context: Context,
+    args: str,
+):
+    run_in_current_directory(context, ["update"] + args.split())
+
+
+@when('I run stenciler set "{assignment}" in the current directory')
+def step_impl(
+    context: Context,
+    assignment: str,
+):
+    run_in_current_directory(context, ["set", assignment])
+
+
+@when("I run stenciler params with JSON output")
+def step_impl(
+    context: Context,
+):
+    stenciler = os.path.join(os.getcwd(), "stenciler")
+    command = [stenciler, "params", "-o", "json", "-r", context.input_dir.name]
+
+    params = subprocess.run(
+        command,
+        cwd=context.output_dir.name,
+        text=True,
+        capture_output=True,
+        check=False,
+    )
+
+    print("STDOUT: ", params.stdout)
+    print("STDERR: ", params.stderr)
+    assert params.returncode == 0
+    context.params_output = params.stdout
@@ -47,3 +47,14 @@ Feature: Local Template Processing
     Given I have a local updated template with init-only files
     When I run stenciler update in the current directory
     Then I see the current directory updated with the template data
+
+  Scenario: Setting the value of a param
+    Given I have a local updated template with a recorded value
+    When I run stenciler set "ship=Reaver" in the current directory
+    Then I see the ship "Reaver" in the current directory
+
+  @prompt
+  Scenario: Prompting again for a param offers the recorded value
+    Given I have a local updated template with a recorded value
+    When I run stenciler update with "--reprompt ship" in the current directory
+    Then I see the ship "Serenity" in the current directory
@@ -0,0 +1,38 @@
+package files
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/rogueserenity/stenciler/config"
+)
+
+// List returns the sorted paths, relative to the current working directory, of the files that CopyRaw and
+// CopyTemplated will write for the template.
+func List(repoDir string, template *config.Template) ([]string, error) {
+	srcRootPath := filepath.Join(repoDir, template.Directory)
+
+	fileList, err := createSourceFileList(srcRootPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate file list: %w", err)
+	}
+
+	if template.Update {
+		initOnlyList, err := createFileList(srcRootPath, template.InitOnlyPaths)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate init-only list: %w", err)
+		}
+		fileList = removeFromFileList(fileList, initOnlyList)
+	}
+
+	var regularFiles []string
+	for _, f := range fileList {
+		if isRegularFile(srcRootPath, f) {
+			regularFiles = append(regularFiles, f)
+		}
+	}
+	slices.Sort(regularFiles)
+
+	return regularFiles, nil
+}
@@ -1,6 +1,7 @@
 package files
 
 import (
+	"bytes"
 	"fmt"
 	"log/slog"
 	"os"
@@ -22,10 +23,7 @@ func CopyTemplated(repoDir string, tmplate *config.Template) error {
 		return fmt.Errorf("failed to get current working directory: %w", err)
 	}
 
-	params := make(map[string]string)
-	for _, p := range tmplate.Params {
-		params[p.Name] = p.Value
-	}
+	data := templateData(tmplate)
 
 	fileList, err := createSourceFileList(srcRootPath)
 	if err != nil {
@@ -48,7 +46,7 @@ func CopyTemplated(repoDir string, tmplate *config.Template) error {
 	}
 
 	for _, f := range fileList {
-		err = copyTemplatedFile(srcRootPath, destRootPath, f, params)
+		err = copyTemplatedFile(repoDir, srcRootPath, destRootPath, f, tmplate, data)
 		if err != nil {
 			return fmt.Errorf("failed to copy %s: %w", f, err)
 		}
@@ -57,6 +55,24 @@ func CopyTemplated(repoDir string, tmplate *config.Template) error {
 	return nil
 }
 
+// templateData returns the data the template files are executed with: the value of each param and each output published
+// by the pre hooks keyed by name and the built-in values under the reserved name.
+func templateData(tmplate *config.Template) map[string]any {
+	data := make(map[string]any, len(tmplate.Params)+len(tmplate.HookOutputs)+1)
+	for name, value := range tmplate.HookOutputs {
+		data[name] = value
+	}
+	for _, p := range tmplate.Params {
+		data[p.Name] = p.Value
+	}
+	builtIns := tmplate.BuiltIns
+	if builtIns == nil {
+		builtIns = &config.BuiltIns{}
+	}
+	data[config.BuiltInsName] = builtIns
+	return data
+}
+
 func createSourceFileList(root string) ([]string, error) {
 	srcRoot := os.DirFS(root)
 	allFiles, err := doublestar.Glob(srcRoot, "**")
@@ -66,7 +82,10 @@ func createSourceFileList(root string) ([]string, error) {
 	return allFiles, nil
 }
 
-func copyTemplatedFile(srcRootPath, destRootPath, relFilePath string, params map[string]string) error {
+func copyTemplatedFile(
+	repoDir, srcRootPath, destRootPath, relFilePath string,
+	tmplate *config.Template,
+	data map[string]any) error {
 	if !isRegularFile(srcRootPath, relFilePath) {
 		return nil
 	}
@@ -87,6 +106,17 @@ func copyTemplatedFile(srcRootPath, destRootPath, relFilePath string, params map
 		return fmt.Errorf("failed to get source file info: %w", err)
 	}
 
+	var rendered bytes.Buffer
+	err = templateFile.Execute(&rendered, data)
+	if err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	content, err := tmplate.ProcessFile(repoDir, relFilePath, rendered.Bytes())
+	if err != nil {
+		return err
+	}
+
 	destFilePath := filepath.Join(destRootPath, relFilePath)
 	destFile, err := os.Create(destFilePath)
 	if err != nil {
@@ -99,9 +129,9 @@ func copyTemplatedFile(srcRootPath, destRootPath, relFilePath string, params map
 		return fmt.Errorf("failed to set destination file permissions: %w", err)
 	}
 
-	err = templateFile.Execute(destFile, params)
+	_, err = destFile.Write(content)
 	if err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+		return fmt.Errorf("failed to write destination file: %w", err)
 	}
 
 	slog.Debug("copied templated file",
@@ -4,6 +4,7 @@
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 
@@ -102,3 +103,145 @@ func (s *CopyTemplatedTestSuite) TestCopyTemplated() {
 	s.Require().NoError(err)
 	s.Equal("bar", string(b))
 }
+
+func (s *CopyTemplatedTestSuite) TestCopyTemplatedWithBuiltIns() {
+	srcDir := s.T().TempDir()
+	err := os.MkdirAll(path.Join(srcDir, "root"), 0755)
+	s.Require().NoError(err)
+	err = os.WriteFile(path.Join(srcDir, "root", "built-ins.txt"),
+		[]byte("{{.Stenciler.TargetDir}} {{.Stenciler.Mode}} {{.Stenciler.Date}} {{.Stenciler.Now.Year}}"), 0644)
+	s.Require().NoError(err)
+
+	template := &config.Template{
+		Directory: "root",
+		BuiltIns: &config.BuiltIns{
+			TargetDir: "serenity",
+			Mode:      config.UpdateMode,
+			Now:       time.Date(2024, time.May, 4, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	err = files.CopyTemplated(srcDir, template)
+	s.Require().NoError(err)
+
+	b, err := os.ReadFile(path.Join(s.destDir, "built-ins.txt"))
+	s.Require().NoError(err)
+	s.Equal("serenity update 2024-05-04 2024", string(b))
+}
+
+func (s *CopyTemplatedTestSuite) TestCopyTemplatedWithHookOutputs() {
+	srcDir := s.T().TempDir()
+	err := os.MkdirAll(path.Join(srcDir, "root"), 0755)
+	s.Require().NoError(err)
+	err = os.WriteFile(path.Join(srcDir, "root", "outputs.txt"), []byte("{{.ship}} {{.port}}"), 0644)
+	s.Require().NoError(err)
+
+	template := &config.Template{
+		Directory: "root",
+		Params: []*config.Param{
+			{
+				Name:  "ship",
+				Value: "serenity",
+			},
+		},
+		HookOutputs: map[string]string{
+			"port": "8080",
+		},
+	}
+
+	err = files.CopyTemplated(srcDir, template)
+	s.Require().NoError(err)
+
+	b, err := os.ReadFile(path.Join(s.destDir, "outputs.txt"))
+	s.Require().NoError(err)
+	s.Equal("serenity 8080", string(b))
+}
+
+func (s *CopyTemplatedTestSuite) TestList() {
+	template := &config.Template{
+		Directory:     "root",
+		InitOnlyPaths: []string{"foo/**"},
+	}
+
+	fileList, err := files.List(s.srcDir, template)
+	s.Require().NoError(err)
+	expected := []string{
+		"bar.txt",
+		"foo.md",
+		"foo/bar.txt",
+		"foo/bar/bar.txt",
+		"foo/bar/baz/bar.txt",
+		"foo/foo.md",
+	}
+	s.Require().Equal(expected, fileList)
+
+	template.Update = true
+	fileList, err = files.List(s.srcDir, template)
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"bar.txt", "foo.md"}, fileList)
+}
+
+func (s *CopyTemplatedTestSuite) TestCopyTemplatedWithProcessors() {
+	srcDir := s.T().TempDir()
+	err := os.MkdirAll(path.Join(srcDir, "root", "cmd"), 0755)
+	s.Require().NoError(err)
+	mainGo := "package main\n{{if .verbose}}\n\n\nimport \"fmt\"\n{{end}}\n" +
+		"func main() {\n{{if .verbose}}fmt.Println(\"hi\"){{end}}\n}\n"
+	err = os.WriteFile(path.Join(srcDir, "root", "cmd", "main.go"), []byte(mainGo), 0644)
+	s.Require().NoError(err)
+	err = os.WriteFile(path.Join(srcDir, "root", "config.json"), []byte(`{"ship": "{{.ship}}",   "crew": [1,2]}`), 0644)
+	s.Require().NoError(err)
+	err = os.WriteFile(path.Join(srcDir, "root", "README.md"), []byte("{{.ship}}\n"), 0644)
+	s.Require().NoError(err)
+
+	template := &config.Template{
+		Directory: "root",
+		Params: []*config.Param{
+			{
+				Name:  "verbose",
+				Value: "true",
+			},
+			{
+				Name:  "ship",
+				Value: "serenity",
+			},
+		},
+		Processors: []*config.Processor{
+			{Glob: "**/*.go", BuiltIn: config.GoFormatProcessor},
+			{Glob: "*.json", BuiltIn: config.JSONProcessor},
+			{Glob: "*.md", Command: "tr a-z A-Z"},
+		},
+	}
+
+	err = files.CopyTemplated(srcDir, template)
+	s.Require().NoError(err)
+
+	b, err := os.ReadFile(path.Join(s.destDir, "cmd", "main.go"))
+	s.Require().NoError(err)
+	s.Equal("package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n", string(b))
+
+	b, err = os.ReadFile(path.Join(s.destDir, "config.json"))
+	s.Require().NoError(err)
+	s.Equal("{\n  \"ship\": \"serenity\",\n  \"crew\": [\n    1,\n    2\n  ]\n}\n", string(b))
+
+	b, err = os.ReadFile(path.Join(s.destDir, "README.md"))
+	s.Require().NoError(err)
+	s.Equal("SERENITY\n", string(b))
+}
+
+func (s *CopyTemplatedTestSuite) TestCopyTemplatedWithFailingProcessor() {
+	srcDir := s.T().TempDir()
+	err := os.MkdirAll(path.Join(srcDir, "root"), 0755)
+	s.Require().NoError(err)
+	err = os.WriteFile(path.Join(srcDir, "root", "main.go"), []byte("package main\nfunc {\n"), 0644)
+	s.Require().NoError(err)
+
+	template := &config.Template{
+		Directory:  "root",
+		Processors: []*config.Processor{{Glob: "**/*.go", BuiltIn: config.GoFormatProcessor}},
+	}
+
+	err = files.CopyTemplated(srcDir, template)
+	s.Require().ErrorContains(err, "failed to copy main.go: rendered template root/main.go is not valid Go:")
+	s.Require().NoFileExists(path.Join(s.destDir, "main.go"))
+}
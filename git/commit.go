@@ -0,0 +1,22 @@
+package git
+
+import (
+	"fmt"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// HeadCommit returns the hash of the commit checked out in the repository at path.
+func HeadCommit(path string) (string, error) {
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD: %w", err)
+	}
+
+	return head.Hash().String(), nil
+}
@@ -0,0 +1,79 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	format "github.com/go-git/go-git/v5/plumbing/format/config"
+)
+
+// ConfigValue returns the value of a git config key, e.g. user.name, as seen from the directory dir. If dir is a
+// repository its config is checked first, followed by the global and then the system config. An empty string is
+// returned if the key is not set.
+func ConfigValue(dir, key string) (string, error) {
+	section, subsection, option, err := splitConfigKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	var configs []*format.Config
+	repo, err := git.PlainOpen(dir)
+	if err == nil {
+		local, err := repo.Config()
+		if err != nil {
+			return "", fmt.Errorf("failed to read repository config: %w", err)
+		}
+		configs = append(configs, local.Raw)
+	} else if !errors.Is(err, git.ErrRepositoryNotExists) {
+		return "", fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	for _, scope := range []config.Scope{config.GlobalScope, config.SystemScope} {
+		cfg, err := config.LoadConfig(scope)
+		if err != nil {
+			return "", fmt.Errorf("failed to read git config: %w", err)
+		}
+		configs = append(configs, cfg.Raw)
+	}
+
+	for _, cfg := range configs {
+		if value := lookupConfigValue(cfg, section, subsection, option); len(value) > 0 {
+			return value, nil
+		}
+	}
+	return "", nil
+}
+
+// splitConfigKey splits a key in the form section[.subsection].option into its parts.
+func splitConfigKey(key string) (string, string, string, error) {
+	section, rest, found := strings.Cut(key, ".")
+	if !found || len(section) == 0 || len(rest) == 0 {
+		return "", "", "", fmt.Errorf("invalid git config key %s", key)
+	}
+	subsection := ""
+	option := rest
+	if i := strings.LastIndex(rest, "."); i >= 0 {
+		subsection, option = rest[:i], rest[i+1:]
+	}
+	if len(option) == 0 {
+		return "", "", "", fmt.Errorf("invalid git config key %s", key)
+	}
+	return section, subsection, option, nil
+}
+
+func lookupConfigValue(cfg *format.Config, section, subsection, option string) string {
+	if !cfg.HasSection(section) {
+		return ""
+	}
+	s := cfg.Section(section)
+	if len(subsection) == 0 {
+		return s.Option(option)
+	}
+	if !s.HasSubsection(subsection) {
+		return ""
+	}
+	return s.Subsection(subsection).Option(option)
+}
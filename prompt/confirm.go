@@ -0,0 +1,111 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rogueserenity/stenciler/config"
+)
+
+// ErrAborted is returned when the user aborts instead of confirming the write.
+var ErrAborted = errors.New("aborted by user")
+
+// ConfirmWrite shows a summary of the template, param values, files and hooks that are about to be written or run and
+// asks the user to confirm, edit a param value or abort. The summary is shown again after a value is edited. It
+// returns ErrAborted if the user aborts.
+func ConfirmWrite(template *config.Template, repoDir string, fileList []string) error {
+	return ConfirmWriteWithInOut(template, repoDir, fileList, stdin(), os.Stdout)
+}
+
+// ConfirmWriteWithInOut shows a summary of the template, param values, files and hooks that are about to be written or
+// run and asks the user to confirm, edit a param value or abort. The summary is shown again after a value is edited.
+// It returns ErrAborted if the user aborts. It uses the provided input and output streams.
+func ConfirmWriteWithInOut(
+	template *config.Template,
+	repoDir string,
+	fileList []string,
+	in io.Reader,
+	out io.Writer) error {
+	input := newInput(in)
+
+	printSummary(template, fileList, out)
+	for {
+		fmt.Fprint(out, "confirm, edit or abort [confirm]: ")
+		choice, err := input.readLine()
+		if err != nil {
+			return fmt.Errorf("error reading response: %w", err)
+		}
+
+		switch strings.ToLower(choice) {
+		case "", "c", "confirm", "y", "yes":
+			return nil
+		case "a", "abort", "n", "no":
+			return ErrAborted
+		case "e", "edit":
+			if err := editParam(template, repoDir, input, out); err != nil {
+				return err
+			}
+			printSummary(template, fileList, out)
+		default:
+			fmt.Fprintf(out, "unknown choice %s\n", choice)
+		}
+	}
+}
+
+func printSummary(template *config.Template, fileList []string, out io.Writer) {
+	fmt.Fprintf(out, "template: %s (%s)\n", template.Repository, template.Directory)
+
+	params := make([]string, 0, len(template.Params))
+	for _, p := range template.Params {
+		params = append(params, fmt.Sprintf("%s = %s", p.Name, p.DisplayValue()))
+	}
+	printSummaryList(out, "params", params)
+
+	printSummaryList(out, "files", fileList)
+
+	if template.SkipHooks {
+		fmt.Fprintln(out, "hooks: skipped")
+		return
+	}
+	printSummaryList(out, "hooks", hookList(template, false))
+}
+
+func printSummaryList(out io.Writer, title string, items []string) {
+	if len(items) == 0 {
+		fmt.Fprintf(out, "%s: none\n", title)
+		return
+	}
+	printList(out, title, items)
+}
+
+// editParam asks the user which prompted param to edit and prompts for a new value for it.
+func editParam(template *config.Template, repoDir string, in *input, out io.Writer) error {
+	var names []string
+	for _, p := range template.Params {
+		if len(p.Prompt) > 0 {
+			names = append(names, p.Name)
+		}
+	}
+	if len(names) == 0 {
+		fmt.Fprintln(out, "there are no params to edit")
+		return nil
+	}
+
+	fmt.Fprintf(out, "param to edit (%s): ", strings.Join(names, "/"))
+	name, err := in.readLine()
+	if err != nil {
+		return fmt.Errorf("error reading response: %w", err)
+	}
+
+	for _, p := range template.Params {
+		if p.Name == name && len(p.Prompt) > 0 {
+			p.Value = ""
+			return processParam(template, p, repoDir, in, out)
+		}
+	}
+	fmt.Fprintf(out, "unknown param %s\n", name)
+	return nil
+}
@@ -0,0 +1,134 @@
+package prompt_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/go-faker/faker/v4"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rogueserenity/stenciler/config"
+	"github.com/rogueserenity/stenciler/prompt"
+)
+
+type PromptConfirmTestSuite struct {
+	suite.Suite
+
+	repoDir  string
+	template *config.Template
+	files    []string
+
+	stdin  *bytes.Buffer
+	stdout *strings.Builder
+}
+
+func TestPromptConfirmTestSuite(t *testing.T) {
+	suite.Run(t, new(PromptConfirmTestSuite))
+}
+
+func (s *PromptConfirmTestSuite) SetupTest() {
+	s.repoDir = faker.Word()
+	s.template = &config.Template{
+		Repository: "https://github.com/owner/repo.git",
+		Directory:  "foo",
+		Params: []*config.Param{
+			{
+				Name:   "ship",
+				Prompt: "Ship",
+				Value:  "Serenity",
+			},
+			{
+				Name:   "token",
+				Prompt: "Token",
+				Secret: true,
+				Value:  "hunter2",
+			},
+		},
+		PreInitHooks:    []*config.Hook{{Path: "hooks/pre.sh"}},
+		PostInitHooks:   []*config.Hook{{Path: "hooks/post.sh"}},
+		PreUpdateHooks:  []*config.Hook{{Path: "hooks/pre-update.sh"}},
+		PostUpdateHooks: []*config.Hook{{Path: "hooks/post-update.sh"}},
+	}
+	s.files = []string{"README.md", "main.go"}
+
+	s.stdin = &bytes.Buffer{}
+	s.stdout = &strings.Builder{}
+}
+
+func (s *PromptConfirmTestSuite) TestConfirm() {
+	s.stdin.WriteString("\n")
+
+	err := prompt.ConfirmWriteWithInOut(s.template, s.repoDir, s.files, s.stdin, s.stdout)
+	s.Require().NoError(err)
+
+	expectedOutput := "template: https://github.com/owner/repo.git (foo)\n" +
+		"params:\n" +
+		"  - ship = Serenity\n" +
+		"  - token = [REDACTED]\n" +
+		"files:\n" +
+		"  - README.md\n" +
+		"  - main.go\n" +
+		"hooks:\n" +
+		"  - pre-init: hooks/pre.sh\n" +
+		"  - post-init: hooks/post.sh\n" +
+		"confirm, edit or abort [confirm]: "
+	s.Equal(expectedOutput, s.stdout.String())
+}
+
+func (s *PromptConfirmTestSuite) TestConfirmUpdate() {
+	s.template.Update = true
+	s.template.Params = nil
+	s.files = nil
+	s.stdin.WriteString("yes\n")
+
+	err := prompt.ConfirmWriteWithInOut(s.template, s.repoDir, s.files, s.stdin, s.stdout)
+	s.Require().NoError(err)
+
+	expectedOutput := "template: https://github.com/owner/repo.git (foo)\n" +
+		"params: none\n" +
+		"files: none\n" +
+		"hooks:\n" +
+		"  - pre-update: hooks/pre-update.sh\n" +
+		"  - post-update: hooks/post-update.sh\n" +
+		"confirm, edit or abort [confirm]: "
+	s.Equal(expectedOutput, s.stdout.String())
+}
+
+func (s *PromptConfirmTestSuite) TestAbort() {
+	s.stdin.WriteString("maybe\nabort\n")
+
+	err := prompt.ConfirmWriteWithInOut(s.template, s.repoDir, s.files, s.stdin, s.stdout)
+	s.Require().ErrorIs(err, prompt.ErrAborted)
+	s.Contains(s.stdout.String(), "unknown choice maybe\nconfirm, edit or abort [confirm]: ")
+}
+
+func (s *PromptConfirmTestSuite) TestEdit() {
+	s.stdin.WriteString("edit\nship\nFirefly\nc\n")
+
+	err := prompt.ConfirmWriteWithInOut(s.template, s.repoDir, s.files, s.stdin, s.stdout)
+	s.Require().NoError(err)
+	s.Equal("Firefly", s.template.Params[0].Value)
+	s.Equal(config.PromptedValue, s.template.Params[0].Source)
+	s.Contains(s.stdout.String(), "param to edit (ship/token): Ship: template:")
+	s.Contains(s.stdout.String(), "  - ship = Firefly\n")
+}
+
+func (s *PromptConfirmTestSuite) TestEditUnknownParam() {
+	s.stdin.WriteString("e\nshp\na\n")
+
+	err := prompt.ConfirmWriteWithInOut(s.template, s.repoDir, s.files, s.stdin, s.stdout)
+	s.Require().ErrorIs(err, prompt.ErrAborted)
+	s.Equal("Serenity", s.template.Params[0].Value)
+	s.Contains(s.stdout.String(), "unknown param shp\n")
+}
+
+func (s *PromptConfirmTestSuite) TestSkippedHooks() {
+	s.template.SkipHooks = true
+	s.stdin.WriteString("\n")
+
+	err := prompt.ConfirmWriteWithInOut(s.template, s.repoDir, s.files, s.stdin, s.stdout)
+	s.Require().NoError(err)
+	s.Require().Contains(s.stdout.String(), "hooks: skipped\n")
+	s.Require().NotContains(s.stdout.String(), "hooks/pre.sh")
+}
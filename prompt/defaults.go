@@ -0,0 +1,62 @@
+package prompt
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/rogueserenity/stenciler/config"
+	"github.com/rogueserenity/stenciler/git"
+)
+
+// resolveDefault returns the default value for the param. The previous value of a param that is prompted for again
+// takes precedence, followed by a per-user default for the param, then the first of the param's default sources that
+// has a value, falling back to the param's default.
+func resolveDefault(param *config.Param, template *config.Template) string {
+	if value := template.PreviousValues[param.Name]; len(value) > 0 {
+		return value
+	}
+	if value := template.UserDefaults[param.Name]; len(value) > 0 {
+		return value
+	}
+	for _, s := range param.DefaultFrom {
+		source, err := config.ParseDefaultSource(s)
+		if err != nil {
+			slog.Debug("skipping invalid default source", slog.String("param", param.Name), slog.Any("error", err))
+			continue
+		}
+		if value := defaultSourceValue(source, template); len(value) > 0 {
+			return value
+		}
+	}
+	return param.Default
+}
+
+func defaultSourceValue(source config.DefaultSource, template *config.Template) string {
+	switch source.Kind {
+	case config.GitDefaultSource:
+		value, err := git.ConfigValue(".", source.Key)
+		if err != nil {
+			slog.Debug("unable to read git config", slog.String("key", source.Key), slog.Any("error", err))
+		}
+		return value
+	case config.EnvDefaultSource:
+		return os.Getenv(source.Key)
+	case config.TargetDirDefaultSource:
+		return targetDir(template)
+	default:
+		return ""
+	}
+}
+
+// targetDir returns the base name of the directory being initialized.
+func targetDir(template *config.Template) string {
+	if template.BuiltIns != nil {
+		return template.BuiltIns.TargetDir
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(cwd)
+}
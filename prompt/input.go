@@ -0,0 +1,112 @@
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// input reads responses from the user. When it wraps a terminal, secret responses are read without echo.
+type input struct {
+	reader *bufio.Reader
+	// fd is the file descriptor of the terminal being read from, or -1 if the input is not a terminal.
+	fd int
+}
+
+// stdin is shared by all prompts that read from standard input so that input buffered by one prompt is not lost to
+// the next.
+var stdin = sync.OnceValue(func() *input {
+	return newInput(os.Stdin)
+})
+
+// Interactive returns true if standard input is a terminal, so that the user can answer prompts as they are shown.
+func Interactive() bool {
+	return stdin().fd >= 0
+}
+
+// newInput wraps the reader for prompting. If the reader is already an *input, it is returned unchanged.
+func newInput(in io.Reader) *input {
+	if i, ok := in.(*input); ok {
+		return i
+	}
+	fd := -1
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		fd = int(f.Fd())
+	}
+	return &input{
+		reader: bufio.NewReader(in),
+		fd:     fd,
+	}
+}
+
+// terminal holds the state of the terminal while a secret is read from it so that echo can be turned back on if
+// stenciler is stopped by a signal in the middle of reading.
+var terminal struct {
+	sync.Mutex
+	fd    int
+	state *term.State
+}
+
+// RestoreTerminal restores the state of the terminal if stenciler is in the middle of reading a secret from it. It is
+// called before stenciler exits on a signal so that the shell is not left with echo turned off.
+func RestoreTerminal() {
+	terminal.Lock()
+	defer terminal.Unlock()
+	if terminal.state == nil {
+		return
+	}
+	if err := term.Restore(terminal.fd, terminal.state); err != nil {
+		slog.Debug("unable to restore terminal", slog.Any("error", err))
+	}
+	terminal.state = nil
+}
+
+func saveTerminalState(fd int) {
+	state, err := term.GetState(fd)
+	if err != nil {
+		slog.Debug("unable to save terminal state", slog.Any("error", err))
+		return
+	}
+	terminal.Lock()
+	defer terminal.Unlock()
+	terminal.fd = fd
+	terminal.state = state
+}
+
+func clearTerminalState() {
+	terminal.Lock()
+	defer terminal.Unlock()
+	terminal.state = nil
+}
+
+// Read implements io.Reader by reading from the buffered input.
+func (i *input) Read(p []byte) (int, error) {
+	return i.reader.Read(p)
+}
+
+// readLine reads a single line of input with surrounding whitespace removed.
+func (i *input) readLine() (string, error) {
+	return readParamPromptResponse(i.reader)
+}
+
+// readSecret reads a single line of input without echoing it back when the input is a terminal. Otherwise, it
+// behaves the same as readLine.
+func (i *input) readSecret(out io.Writer) (string, error) {
+	if i.fd < 0 {
+		return i.readLine()
+	}
+	saveTerminalState(i.fd)
+	defer clearTerminalState()
+	b, err := term.ReadPassword(i.fd)
+	fmt.Fprintln(out)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
@@ -2,6 +2,7 @@
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -13,16 +14,21 @@
 // ForParamValues prompts the user for values for any parameters that have a prompt defined and does not
 // currently have a value associated. It will validate all values for params with a prompt defined.
 func ForParamValues(template *config.Template, repoDir string) error {
-	return ForParamValuesWithInOut(template, repoDir, os.Stdin, os.Stdout)
+	return ForParamValuesWithInOut(template, repoDir, stdin(), os.Stdout)
 }
 
 // ForParamValuesWithInOut prompts the user for values for any parameters that have a prompt defined and does not
-// currently have a value associated. It will validate all values for params with a prompt defined.
+// currently have a value associated. It will validate all values for params with a prompt defined. The source of each
+// value is recorded on the param.
 func ForParamValuesWithInOut(template *config.Template, repoDir string, in io.Reader, out io.Writer) error {
-	bufIn := bufio.NewReader(in)
+	input := newInput(in)
 
 	for _, p := range template.Params {
-		if err := processParam(p, repoDir, bufIn, out); err != nil {
+		if len(p.Prompt) == 0 {
+			p.Source = config.InternalValue
+			continue
+		}
+		if err := processParam(template, p, repoDir, input, out); err != nil {
 			return fmt.Errorf("error processing param: %w", err)
 		}
 	}
@@ -30,34 +36,99 @@ func ForParamValuesWithInOut(template *config.Template, repoDir string, in io.Re
 	return nil
 }
 
-func processParam(param *config.Param, repoDir string, in *bufio.Reader, out io.Writer) error {
-	if len(param.Prompt) == 0 {
-		return nil
-	}
+// helpResponse is the response a user enters at a param prompt to see the help for the param. It is only treated as a
+// request for help if the param has help to show, otherwise it is taken as the value.
+const helpResponse = "?"
 
-	if len(param.Value) == 0 {
-		printParamPrompt(param, out)
-		val, err := readParamPromptResponse(in)
-		if err != nil {
-			return fmt.Errorf("error reading response: %w", err)
+// processParam prompts for a value if one is not already set and validates it. The resolved default is used if the user
+// does not enter a value. If the value is rejected, the reason is shown and the user is prompted again. Entering
+// helpResponse shows the help for the param, if it has any, and prompts again. Secret values are read without echo.
+func processParam(template *config.Template, param *config.Param, repoDir string, in *input, out io.Writer) error {
+	defaultValue := resolveDefault(param, template)
+	var validationErr *config.ValidationError
+	for {
+		if len(param.Value) == 0 {
+			printParamPrompt(param, defaultValue, out)
+			val, err := readParamResponse(param, in, out)
+			if err != nil {
+				if validationErr != nil {
+					return validationErr
+				}
+				return fmt.Errorf("error reading response: %w", err)
+			}
+			if val == helpResponse && hasHelp(param) {
+				printParamHelp(param, out)
+				continue
+			}
+			param.Source = config.PromptedValue
+			if len(val) == 0 {
+				val = defaultValue
+				param.Source = config.DefaultValue
+			}
+			param.Value = val
 		}
-		if len(val) == 0 {
-			val = param.Default
+
+		err := template.ValidateParam(param, repoDir)
+		if !errors.As(err, &validationErr) {
+			return err
 		}
-		param.Value = val
+		fmt.Fprintf(out, "invalid value: %s\n", validationErr)
+		param.Value = ""
 	}
-
-	return param.Validate(repoDir)
 }
 
-func printParamPrompt(param *config.Param, out io.Writer) {
+func printParamPrompt(param *config.Param, defaultValue string, out io.Writer) {
 	fmt.Fprint(out, param.Prompt)
-	if len(param.Default) > 0 {
-		fmt.Fprintf(out, " [%s]", param.Default)
+	var hints []string
+	if len(param.AllowedValues) > 0 {
+		hints = append(hints, strings.Join(param.AllowedValues, "/"))
+	}
+	if hasHelp(param) {
+		hints = append(hints, helpResponse+" for help")
+	}
+	if len(hints) > 0 {
+		fmt.Fprintf(out, " (%s)", strings.Join(hints, ", "))
+	}
+	if len(defaultValue) > 0 {
+		fmt.Fprintf(out, " [%s]", defaultValue)
 	}
 	fmt.Fprint(out, ": ")
 }
 
+// hasHelp returns true if the param has any information to show beyond its prompt.
+func hasHelp(param *config.Param) bool {
+	return len(param.Description) > 0 || len(param.Help) > 0 || len(param.Examples) > 0 || len(param.Rules()) > 0
+}
+
+// printParamHelp shows the description, help text, rules and examples for the param.
+func printParamHelp(param *config.Param, out io.Writer) {
+	if len(param.Description) > 0 {
+		fmt.Fprintln(out, param.Description)
+	}
+	if len(param.Help) > 0 {
+		fmt.Fprintln(out, strings.TrimSpace(param.Help))
+	}
+	printList(out, "rules", param.Rules())
+	printList(out, "examples", param.Examples)
+}
+
+func printList(out io.Writer, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "%s:\n", title)
+	for _, item := range items {
+		fmt.Fprintf(out, "  - %s\n", item)
+	}
+}
+
+func readParamResponse(param *config.Param, in *input, out io.Writer) (string, error) {
+	if param.Secret {
+		return in.readSecret(out)
+	}
+	return in.readLine()
+}
+
 func readParamPromptResponse(reader *bufio.Reader) (string, error) {
 	value, err := reader.ReadString('\n')
 	if err != nil {
@@ -2,6 +2,8 @@
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -124,3 +126,327 @@ func (s *PromptParamsTestSuite) TestForParamValuesWithPromptAndDefaultValueAndIn
 	s.Equal(expectedOutput, s.stdout.String())
 	s.Equal("input_value", template.Params[0].Value)
 }
+
+func (s *PromptParamsTestSuite) TestForParamValuesRepromptsOnRuleFailure() {
+	template := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:     "foo",
+				Prompt:   "Enter a value for foo",
+				Required: true,
+			},
+		},
+	}
+
+	s.stdin.WriteString("\nbaz\n")
+
+	err := prompt.ForParamValuesWithInOut(template, s.repoDir, s.stdin, s.stdout)
+	s.NoError(err)
+
+	expectedOutput := "Enter a value for foo (? for help): invalid value: a value for foo is required\n" +
+		"Enter a value for foo (? for help): "
+	s.Equal(expectedOutput, s.stdout.String())
+	s.Equal("baz", template.Params[0].Value)
+}
+
+func (s *PromptParamsTestSuite) TestForParamValuesRepromptsOnHookRejection() {
+	repoDir := s.T().TempDir()
+	contents := `#!/bin/sh
+if [ "$2" != "baz" ]; then
+	echo "only baz is allowed" >&2
+	exit 65
+fi
+echo "$2"
+`
+	err := os.WriteFile(filepath.Join(repoDir, "hook.sh"), []byte(contents), 0755)
+	s.Require().NoError(err)
+
+	template := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:           "foo",
+				Prompt:         "Enter a value for foo",
+				ValidationHook: &config.Hook{Path: "hook.sh"},
+			},
+		},
+	}
+
+	s.stdin.WriteString("bar\nbaz\n")
+
+	err = prompt.ForParamValuesWithInOut(template, repoDir, s.stdin, s.stdout)
+	s.NoError(err)
+
+	expectedOutput := "Enter a value for foo: invalid value: only baz is allowed\nEnter a value for foo: "
+	s.Equal(expectedOutput, s.stdout.String())
+	s.Equal("baz", template.Params[0].Value)
+}
+
+func (s *PromptParamsTestSuite) TestForParamValuesReturnsRejectionWhenInputEnds() {
+	template := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:          "foo",
+				Prompt:        "Enter a value for foo",
+				AllowedValues: []string{"baz"},
+			},
+		},
+	}
+
+	s.stdin.WriteString("bar\n")
+
+	err := prompt.ForParamValuesWithInOut(template, s.repoDir, s.stdin, s.stdout)
+	s.ErrorContains(err, `value "bar" for foo must be one of: baz`)
+}
+
+func (s *PromptParamsTestSuite) TestForParamValuesWithSecret() {
+	template := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:   "token",
+				Prompt: "Enter a token",
+				Secret: true,
+			},
+		},
+	}
+
+	s.stdin.WriteString("hunter2\n")
+
+	err := prompt.ForParamValuesWithInOut(template, s.repoDir, s.stdin, s.stdout)
+	s.NoError(err)
+
+	s.Equal("Enter a token: ", s.stdout.String())
+	s.Equal("hunter2", template.Params[0].Value)
+}
+
+func (s *PromptParamsTestSuite) TestForParamValuesShowsAllowedValues() {
+	template := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:          "foo",
+				Prompt:        "Enter a value for foo",
+				Default:       "bar",
+				AllowedValues: []string{"bar", "baz"},
+			},
+		},
+	}
+
+	s.stdin.WriteString("baz\n")
+
+	err := prompt.ForParamValuesWithInOut(template, s.repoDir, s.stdin, s.stdout)
+	s.NoError(err)
+
+	expectedOutput := "Enter a value for foo (bar/baz, ? for help) [bar]: "
+	s.Equal(expectedOutput, s.stdout.String())
+	s.Equal("baz", template.Params[0].Value)
+}
+
+func (s *PromptParamsTestSuite) TestForParamValuesShowsHelp() {
+	template := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:        "foo",
+				Prompt:      "Enter a value for foo",
+				Description: "The foo of the project.",
+				Help:        "Foo is used to name things.\n",
+				Examples:    []string{"bar", "baz"},
+				Pattern:     "[a-z]+",
+			},
+		},
+	}
+
+	s.stdin.WriteString("?\nbar\n")
+
+	err := prompt.ForParamValuesWithInOut(template, s.repoDir, s.stdin, s.stdout)
+	s.NoError(err)
+
+	expectedOutput := "Enter a value for foo (? for help): " +
+		"The foo of the project.\n" +
+		"Foo is used to name things.\n" +
+		"rules:\n" +
+		"  - must match [a-z]+\n" +
+		"examples:\n" +
+		"  - bar\n" +
+		"  - baz\n" +
+		"Enter a value for foo (? for help): "
+	s.Equal(expectedOutput, s.stdout.String())
+	s.Equal("bar", template.Params[0].Value)
+}
+
+func (s *PromptParamsTestSuite) TestForParamValuesAcceptsQuestionMarkWithoutHelp() {
+	template := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:   "foo",
+				Prompt: "Enter a value for foo",
+			},
+		},
+	}
+
+	s.stdin.WriteString("?\n")
+
+	err := prompt.ForParamValuesWithInOut(template, s.repoDir, s.stdin, s.stdout)
+	s.NoError(err)
+
+	s.Equal("Enter a value for foo: ", s.stdout.String())
+	s.Equal("?", template.Params[0].Value)
+}
+
+func (s *PromptParamsTestSuite) TestForParamValuesWithDefaultSources() {
+	home := s.T().TempDir()
+	s.T().Setenv("HOME", home)
+	s.T().Setenv("XDG_CONFIG_HOME", home)
+	gitConfig := "[user]\n\tname = Malcolm Reynolds\n\temail = mal@serenity.example\n"
+	err := os.WriteFile(filepath.Join(home, ".gitconfig"), []byte(gitConfig), 0644)
+	s.Require().NoError(err)
+	s.T().Setenv("STENCILER_TEST_ORG", "browncoats")
+	s.T().Setenv("STENCILER_TEST_EMPTY", "")
+
+	template := &config.Template{
+		BuiltIns: &config.BuiltIns{
+			TargetDir: "serenity",
+		},
+		Params: []*config.Param{
+			{
+				Name:        "author",
+				Prompt:      "Author",
+				DefaultFrom: []string{"env:STENCILER_TEST_EMPTY", "git:user.name"},
+				Default:     "anonymous",
+			},
+			{
+				Name:        "org",
+				Prompt:      "Org",
+				DefaultFrom: []string{"env:STENCILER_TEST_ORG", "git:user.name"},
+			},
+			{
+				Name:        "project",
+				Prompt:      "Project",
+				DefaultFrom: []string{"target-dir"},
+			},
+			{
+				Name:        "license",
+				Prompt:      "License",
+				DefaultFrom: []string{"git:stenciler.license"},
+				Default:     "MIT",
+			},
+		},
+	}
+
+	s.stdin.WriteString("\n\n\n\n")
+
+	err = prompt.ForParamValuesWithInOut(template, s.repoDir, s.stdin, s.stdout)
+	s.NoError(err)
+
+	expectedOutput := "Author [Malcolm Reynolds]: Org [browncoats]: Project [serenity]: License [MIT]: "
+	s.Equal(expectedOutput, s.stdout.String())
+	s.Equal("Malcolm Reynolds", template.Params[0].Value)
+	s.Equal("browncoats", template.Params[1].Value)
+	s.Equal("serenity", template.Params[2].Value)
+	s.Equal("MIT", template.Params[3].Value)
+	s.Equal("anonymous", template.Params[0].Default)
+}
+
+func (s *PromptParamsTestSuite) TestForParamValuesWithUserDefaults() {
+	s.T().Setenv("STENCILER_TEST_ORG", "browncoats")
+
+	template := &config.Template{
+		UserDefaults: map[string]string{
+			"org":    "alliance",
+			"author": "",
+		},
+		Params: []*config.Param{
+			{
+				Name:        "org",
+				Prompt:      "Org",
+				DefaultFrom: []string{"env:STENCILER_TEST_ORG"},
+				Default:     "independents",
+			},
+			{
+				Name:    "author",
+				Prompt:  "Author",
+				Default: "anonymous",
+			},
+		},
+	}
+
+	s.stdin.WriteString("\n\n")
+
+	err := prompt.ForParamValuesWithInOut(template, s.repoDir, s.stdin, s.stdout)
+	s.NoError(err)
+
+	expectedOutput := "Org [alliance]: Author [anonymous]: "
+	s.Equal(expectedOutput, s.stdout.String())
+	s.Equal("alliance", template.Params[0].Value)
+	s.Equal(config.DefaultValue, template.Params[0].Source)
+	s.Equal("anonymous", template.Params[1].Value)
+}
+
+func (s *PromptParamsTestSuite) TestForParamValuesWithPreviousValues() {
+	template := &config.Template{
+		PreviousValues: map[string]string{"org": "browncoats"},
+		UserDefaults:   map[string]string{"org": "alliance"},
+		Params: []*config.Param{
+			{
+				Name:    "org",
+				Prompt:  "Org",
+				Default: "independents",
+			},
+		},
+	}
+
+	s.stdin.WriteString("\n")
+
+	err := prompt.ForParamValuesWithInOut(template, s.repoDir, s.stdin, s.stdout)
+	s.NoError(err)
+	s.Equal("Org [browncoats]: ", s.stdout.String())
+	s.Equal("browncoats", template.Params[0].Value)
+}
+
+func (s *PromptParamsTestSuite) TestForParamValuesRecordsSource() {
+	repoDir := s.T().TempDir()
+	contents := `#!/bin/sh
+echo "$2" | tr '[:lower:]' '[:upper:]'
+`
+	err := os.WriteFile(filepath.Join(repoDir, "upper.sh"), []byte(contents), 0755)
+	s.Require().NoError(err)
+
+	template := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:  "internal",
+				Value: "value",
+			},
+			{
+				Name:   "prompted",
+				Prompt: "Prompted",
+			},
+			{
+				Name:    "default",
+				Prompt:  "Default",
+				Default: "default_value",
+			},
+			{
+				Name:           "hook",
+				Prompt:         "Hook",
+				ValidationHook: &config.Hook{Path: "upper.sh"},
+			},
+			{
+				Name:   "existing",
+				Prompt: "Existing",
+				Value:  "existing_value",
+				Source: config.FlagValue,
+			},
+		},
+	}
+
+	s.stdin.WriteString("input_value\n\nhook_value\n")
+
+	err = prompt.ForParamValuesWithInOut(template, repoDir, s.stdin, s.stdout)
+	s.Require().NoError(err)
+
+	s.Equal(config.InternalValue, template.Params[0].Source)
+	s.Equal(config.PromptedValue, template.Params[1].Source)
+	s.Equal(config.DefaultValue, template.Params[2].Source)
+	s.Equal(config.HookValue, template.Params[3].Source)
+	s.Equal("HOOK_VALUE", template.Params[3].Value)
+	s.Equal(config.FlagValue, template.Params[4].Source)
+}
@@ -1,7 +1,6 @@
 package prompt
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
 	"io"
@@ -15,7 +14,7 @@
 // SelectTemplate prompts the user to select a template if more than one template is defined in the configuration
 // and no template directory is specified.
 func SelectTemplate(templateDir string, cfg *config.Config) (*config.Template, error) {
-	return SelectTemplateWithInOut(templateDir, cfg, os.Stdin, os.Stdout)
+	return SelectTemplateWithInOut(templateDir, cfg, stdin(), os.Stdout)
 }
 
 // SelectTemplateWithInOut prompts the user to select a template if more than one template is defined in the
@@ -62,7 +61,7 @@ func promptForTemplateDir(
 	templateMap map[string]*config.Template,
 	in io.Reader,
 	out io.Writer) (*config.Template, error) {
-	reader := bufio.NewReader(in)
+	reader := newInput(in).reader
 
 	keys := sortedKeys(templateMap)
 	var template *config.Template
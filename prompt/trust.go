@@ -0,0 +1,131 @@
+package prompt
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rogueserenity/stenciler/config"
+)
+
+// HookTrust is the decision of the user on whether the hooks of a template may be run.
+type HookTrust int
+
+const (
+	// RunHooks allows the hooks to run for the current command.
+	RunHooks HookTrust = iota
+	// SkipHooks skips all hooks for the current command.
+	SkipHooks
+	// AlwaysRunHooks allows the hooks to run for the current command and trusts the repository from now on.
+	AlwaysRunHooks
+)
+
+// ConfirmHooks lists the hooks of the template that may run during the current command and asks the user whether to
+// run them. RunHooks is returned without asking if the template has no hooks.
+func ConfirmHooks(template *config.Template) (HookTrust, error) {
+	return ConfirmHooksWithInOut(template, stdin(), os.Stdout)
+}
+
+// ConfirmHooksWithInOut lists the hooks of the template that may run during the current command and asks the user
+// whether to run them. RunHooks is returned without asking if the template has no hooks. It uses the provided input
+// and output streams.
+func ConfirmHooksWithInOut(template *config.Template, in io.Reader, out io.Writer) (HookTrust, error) {
+	hooks := hookList(template, true)
+	if len(hooks) == 0 {
+		return RunHooks, nil
+	}
+
+	input := newInput(in)
+	fmt.Fprintf(out, "template %s (%s) runs commands on this machine\n", template.Repository, template.Directory)
+	printList(out, "hooks", hooks)
+	printHookEnv(template, out)
+	for {
+		fmt.Fprint(out, "run these hooks? (yes, no, always) [no]: ")
+		choice, err := input.readLine()
+		if err != nil {
+			return SkipHooks, fmt.Errorf("error reading response: %w", err)
+		}
+
+		switch strings.ToLower(choice) {
+		case "y", "yes":
+			return RunHooks, nil
+		case "", "n", "no":
+			fmt.Fprintln(out, "hooks will not be run")
+			return SkipHooks, nil
+		case "a", "always":
+			return AlwaysRunHooks, nil
+		default:
+			fmt.Fprintf(out, "unknown choice %s\n", choice)
+		}
+	}
+}
+
+// hookList describes each hook and processor command that may run during the current command. For the trust prompt,
+// the validation hooks are included and each hook is described by the full command it runs rather than its name.
+func hookList(template *config.Template, forTrust bool) []string {
+	var hooks []string
+	if forTrust {
+		for _, p := range template.Params {
+			// The validation hooks of internal params run when a pre hook sets their value.
+			if p.ValidationHook != nil {
+				hooks = append(hooks, fmt.Sprintf("validate %s: %s", p.Name, describeHook(p.ValidationHook, forTrust)))
+			}
+		}
+	}
+
+	hookClasses := []config.HookClass{config.PreInitHook, config.PostInitHook, config.OnFailureHook}
+	if template.Update {
+		hookClasses = []config.HookClass{config.PreUpdateHook, config.PostUpdateHook, config.OnFailureHook}
+	}
+	for _, hookClass := range hookClasses {
+		classHooks, _ := template.Hooks(hookClass)
+		for _, hook := range classHooks {
+			hooks = append(hooks, fmt.Sprintf("%s: %s", hookClass, describeHook(hook, forTrust)))
+		}
+	}
+	for _, processor := range template.Processors {
+		if len(processor.Command) > 0 {
+			hooks = append(hooks, fmt.Sprintf("process %s: %s", processor.Glob, processor.Command))
+		}
+	}
+	return hooks
+}
+
+// describeHook returns the name of the hook. If full is true and the name does not show what the hook runs, the script
+// or each line of the inline command is added.
+func describeHook(hook *config.Hook, full bool) string {
+	name := hook.String()
+	if !full {
+		return name
+	}
+	if len(hook.Run) > 0 {
+		command := strings.TrimSpace(hook.Run)
+		if name == command {
+			return name
+		}
+		for line := range strings.Lines(command) {
+			name += "\n      " + strings.TrimRight(line, "\n")
+		}
+		return name
+	}
+	script := (&config.Hook{Path: hook.Path, Interpreter: hook.Interpreter}).String()
+	if name == script {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, script)
+}
+
+// printHookEnv describes the environment variables the hooks of the template receive.
+func printHookEnv(template *config.Template, out io.Writer) {
+	if !template.RestrictedHookEnv() {
+		fmt.Fprintln(out, "hooks receive your full environment")
+		return
+	}
+	passed := template.PassedHookEnv()
+	if len(passed) == 0 {
+		fmt.Fprintln(out, "hooks receive only PATH, HOME and the template params")
+		return
+	}
+	fmt.Fprintf(out, "hooks receive only PATH, HOME, the template params and: %s\n", strings.Join(passed, ", "))
+}
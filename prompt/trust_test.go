@@ -0,0 +1,171 @@
+package prompt_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/rogueserenity/stenciler/config"
+	"github.com/rogueserenity/stenciler/prompt"
+)
+
+type PromptTrustTestSuite struct {
+	suite.Suite
+
+	template *config.Template
+
+	stdin  *bytes.Buffer
+	stdout *strings.Builder
+}
+
+func TestPromptTrustTestSuite(t *testing.T) {
+	suite.Run(t, new(PromptTrustTestSuite))
+}
+
+func (s *PromptTrustTestSuite) SetupTest() {
+	s.template = &config.Template{
+		Repository: "https://github.com/owner/repo.git",
+		Directory:  "foo",
+		Params: []*config.Param{
+			{
+				Name:           "ship",
+				Prompt:         "Ship",
+				ValidationHook: &config.Hook{Path: "hooks/validate.sh"},
+			},
+		},
+		PreInitHooks:   []*config.Hook{{Path: "hooks/pre.sh"}},
+		PostInitHooks:  []*config.Hook{{Run: "git init"}},
+		PreUpdateHooks: []*config.Hook{{Path: "hooks/pre-update.sh"}},
+		OnFailureHooks: []*config.Hook{{Run: "rm -rf build"}},
+		Processors: []*config.Processor{
+			{Glob: "**/*.go", BuiltIn: "gofmt"},
+			{Glob: "**/*.tf", Command: "terraform fmt -"},
+		},
+	}
+
+	s.stdin = &bytes.Buffer{}
+	s.stdout = &strings.Builder{}
+}
+
+func (s *PromptTrustTestSuite) TestRun() {
+	s.stdin.WriteString("yes\n")
+
+	trust, err := prompt.ConfirmHooksWithInOut(s.template, s.stdin, s.stdout)
+	s.Require().NoError(err)
+	s.Require().Equal(prompt.RunHooks, trust)
+
+	expected := `template https://github.com/owner/repo.git (foo) runs commands on this machine
+hooks:
+  - validate ship: hooks/validate.sh
+  - pre-init: hooks/pre.sh
+  - post-init: git init
+  - on-failure: rm -rf build
+  - process **/*.tf: terraform fmt -
+hooks receive your full environment
+run these hooks? (yes, no, always) [no]: `
+	s.Require().Equal(expected, s.stdout.String())
+}
+
+func (s *PromptTrustTestSuite) TestSkipByDefault() {
+	s.stdin.WriteString("\n")
+
+	trust, err := prompt.ConfirmHooksWithInOut(s.template, s.stdin, s.stdout)
+	s.Require().NoError(err)
+	s.Require().Equal(prompt.SkipHooks, trust)
+	s.Require().True(strings.HasSuffix(s.stdout.String(), "hooks will not be run\n"))
+}
+
+func (s *PromptTrustTestSuite) TestAlwaysAfterUnknownChoice() {
+	s.stdin.WriteString("maybe\nalways\n")
+
+	trust, err := prompt.ConfirmHooksWithInOut(s.template, s.stdin, s.stdout)
+	s.Require().NoError(err)
+	s.Require().Equal(prompt.AlwaysRunHooks, trust)
+	s.Require().Contains(s.stdout.String(), "unknown choice maybe\n")
+}
+
+func (s *PromptTrustTestSuite) TestUpdateHooks() {
+	s.template.Update = true
+	s.template.Params = nil
+	s.stdin.WriteString("y\n")
+
+	_, err := prompt.ConfirmHooksWithInOut(s.template, s.stdin, s.stdout)
+	s.Require().NoError(err)
+	expected := "hooks:\n" +
+		"  - pre-update: hooks/pre-update.sh\n" +
+		"  - on-failure: rm -rf build\n" +
+		"  - process **/*.tf"
+	s.Require().Contains(s.stdout.String(), expected)
+}
+
+func (s *PromptTrustTestSuite) TestNamedAndMultilineHooks() {
+	s.template.Params = nil
+	s.template.PreInitHooks = []*config.Hook{{Name: "lint", Path: "hooks/lint.sh"}}
+	s.template.PostInitHooks = []*config.Hook{
+		{Name: "tidy", Run: "go mod tidy"},
+		{Run: "echo one\necho two\n"},
+	}
+	s.stdin.WriteString("y\n")
+
+	_, err := prompt.ConfirmHooksWithInOut(s.template, s.stdin, s.stdout)
+	s.Require().NoError(err)
+	expected := "hooks:\n" +
+		"  - pre-init: lint (hooks/lint.sh)\n" +
+		"  - post-init: tidy\n" +
+		"      go mod tidy\n" +
+		"  - post-init: echo one...\n" +
+		"      echo one\n" +
+		"      echo two\n"
+	s.Require().Contains(s.stdout.String(), expected)
+}
+
+func (s *PromptTrustTestSuite) TestInternalParamValidationHook() {
+	s.template.Params = append(s.template.Params, &config.Param{
+		Name:           "port",
+		ValidationHook: &config.Hook{Path: "hooks/port.sh"},
+	})
+	s.stdin.WriteString("y\n")
+
+	_, err := prompt.ConfirmHooksWithInOut(s.template, s.stdin, s.stdout)
+	s.Require().NoError(err)
+	s.Require().Contains(s.stdout.String(), "  - validate port: hooks/port.sh\n")
+}
+
+func (s *PromptTrustTestSuite) TestRestrictedEnv() {
+	s.template.RestrictHookEnv = true
+	s.stdin.WriteString("y\n")
+
+	_, err := prompt.ConfirmHooksWithInOut(s.template, s.stdin, s.stdout)
+	s.Require().NoError(err)
+	s.Require().Contains(s.stdout.String(), "hooks receive only PATH, HOME and the template params\n")
+
+	s.template.HookEnv = &config.HookEnv{Pass: []string{"GOPROXY", "SSH_AUTH_SOCK"}}
+	s.stdin.WriteString("y\n")
+	_, err = prompt.ConfirmHooksWithInOut(s.template, s.stdin, s.stdout)
+	s.Require().NoError(err)
+	s.Require().Contains(s.stdout.String(),
+		"hooks receive only PATH, HOME, the template params and: GOPROXY, SSH_AUTH_SOCK\n")
+}
+
+func (s *PromptTrustTestSuite) TestNoHooks() {
+	template := &config.Template{
+		Params: []*config.Param{
+			{
+				Name:   "ship",
+				Prompt: "Ship",
+			},
+		},
+	}
+
+	trust, err := prompt.ConfirmHooksWithInOut(template, s.stdin, s.stdout)
+	s.Require().NoError(err)
+	s.Require().Equal(prompt.RunHooks, trust)
+	s.Require().Empty(s.stdout.String())
+}
+
+func (s *PromptTrustTestSuite) TestReadError() {
+	_, err := prompt.ConfirmHooksWithInOut(s.template, s.stdin, s.stdout)
+	s.Require().ErrorContains(err, "error reading response")
+}